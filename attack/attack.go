@@ -0,0 +1,134 @@
+// Package attack holds the ATT&CK-style taxonomy of tactics and techniques that reports are tagged
+// with. The taxonomy ships with the binary in techniques.json and is validated when the package loads.
+package attack
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+//go:embed techniques.json
+var bundled []byte
+
+// Tactic is an adversary goal, such as initial-access.
+type Tactic struct {
+	ID   string `json:"id"`
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// Technique is a way of reaching one or more tactics. Sub-techniques (T1059.001) have the
+// technique they refine as Parent.
+type Technique struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Tactics     []string `json:"tactics"`
+	Description string   `json:"description,omitempty"`
+	Parent      string   `json:"parent,omitempty"`
+}
+
+type taxonomy struct {
+	Version    string      `json:"version"`
+	Tactics    []Tactic    `json:"tactics"`
+	Techniques []Technique `json:"techniques"`
+}
+
+var techniqueID = regexp.MustCompile(`^T\d{4}(\.\d{3})?$`)
+
+var (
+	loaded   taxonomy
+	byID     map[string]Technique
+	children map[string][]string
+	tactics  map[string]bool
+)
+
+func init() {
+	if err := load(bundled); err != nil {
+		panic("attack: invalid bundled taxonomy: " + err.Error())
+	}
+}
+
+func load(data []byte) error {
+	var parsed taxonomy
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return err
+	}
+
+	knownTactics := make(map[string]bool, len(parsed.Tactics))
+	for _, tactic := range parsed.Tactics {
+		if tactic.Slug == "" || knownTactics[tactic.Slug] {
+			return fmt.Errorf("tactic %q is empty or duplicated", tactic.Slug)
+		}
+		knownTactics[tactic.Slug] = true
+	}
+
+	techniques := make(map[string]Technique, len(parsed.Techniques))
+	subTechniques := map[string][]string{}
+	for i := range parsed.Techniques {
+		technique := &parsed.Techniques[i]
+		if !techniqueID.MatchString(technique.ID) {
+			return fmt.Errorf("invalid technique ID %q", technique.ID)
+		}
+		if _, ok := techniques[technique.ID]; ok {
+			return fmt.Errorf("technique %s is duplicated", technique.ID)
+		}
+		if technique.Name == "" || len(technique.Tactics) == 0 {
+			return fmt.Errorf("technique %s needs a name and at least one tactic", technique.ID)
+		}
+		for _, tactic := range technique.Tactics {
+			if !knownTactics[tactic] {
+				return fmt.Errorf("technique %s has unknown tactic %q", technique.ID, tactic)
+			}
+		}
+		if parent, _, ok := strings.Cut(technique.ID, "."); ok {
+			if _, found := techniques[parent]; !found {
+				return fmt.Errorf("sub-technique %s must follow its parent %s", technique.ID, parent)
+			}
+			technique.Parent = parent
+			subTechniques[parent] = append(subTechniques[parent], technique.ID)
+		}
+		techniques[technique.ID] = *technique
+	}
+
+	loaded, byID, children, tactics = parsed, techniques, subTechniques, knownTactics
+	return nil
+}
+
+// Version is the version of the bundled taxonomy.
+func Version() string {
+	return loaded.Version
+}
+
+// Tactics lists the tactics in kill chain order.
+func Tactics() []Tactic {
+	return append([]Tactic(nil), loaded.Tactics...)
+}
+
+// Techniques lists every technique, each followed by its sub-techniques.
+func Techniques() []Technique {
+	return append([]Technique(nil), loaded.Techniques...)
+}
+
+// IsTactic reports whether slug names a tactic of the taxonomy.
+func IsTactic(slug string) bool {
+	return tactics[slug]
+}
+
+// Lookup finds a technique by ID, ignoring case and surrounding spaces.
+func Lookup(id string) (Technique, bool) {
+	technique, ok := byID[Normalize(id)]
+	return technique, ok
+}
+
+// Normalize puts a technique ID in its canonical form, e.g. " t1059.001" becomes "T1059.001".
+func Normalize(id string) string {
+	return strings.ToUpper(strings.TrimSpace(id))
+}
+
+// SubTechniques lists the IDs of the sub-techniques refining a technique.
+func SubTechniques(id string) []string {
+	return append([]string(nil), children[Normalize(id)]...)
+}
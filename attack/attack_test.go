@@ -0,0 +1,91 @@
+package attack
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBundledTaxonomy(t *testing.T) {
+	if Version() == "" {
+		t.Error("bundled taxonomy has no version")
+	}
+	if len(Tactics()) != 14 {
+		t.Errorf("got %d tactics, want 14", len(Tactics()))
+	}
+	techniques := Techniques()
+	if len(techniques) == 0 {
+		t.Fatal("bundled taxonomy has no techniques")
+	}
+	for _, technique := range techniques {
+		for _, tactic := range technique.Tactics {
+			if !IsTactic(tactic) {
+				t.Errorf("%s: unknown tactic %q", technique.ID, tactic)
+			}
+		}
+		if technique.Parent != "" {
+			if _, ok := Lookup(technique.Parent); !ok {
+				t.Errorf("%s: unknown parent %s", technique.ID, technique.Parent)
+			}
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	technique, ok := Lookup(" t1059.001 ")
+	if !ok {
+		t.Fatal("T1059.001 not found")
+	}
+	if technique.ID != "T1059.001" || technique.Parent != "T1059" || technique.Name == "" {
+		t.Errorf("unexpected technique %+v", technique)
+	}
+	if _, ok := Lookup("T9999"); ok {
+		t.Error("T9999 found")
+	}
+	if IsTactic("not-a-tactic") {
+		t.Error("unknown tactic accepted")
+	}
+}
+
+func TestSubTechniques(t *testing.T) {
+	subTechniques := SubTechniques("t1059")
+	if len(subTechniques) == 0 {
+		t.Fatal("T1059 has no sub-techniques")
+	}
+	for _, id := range subTechniques {
+		if !strings.HasPrefix(id, "T1059.") {
+			t.Errorf("%s is not a sub-technique of T1059", id)
+		}
+	}
+	if len(SubTechniques("T1059.001")) != 0 {
+		t.Error("sub-techniques have no sub-techniques")
+	}
+
+	// Callers may modify the returned slices
+	subTechniques[0] = "T0000"
+	if reflect.DeepEqual(SubTechniques("T1059"), subTechniques) {
+		t.Error("SubTechniques returned the shared slice")
+	}
+}
+
+func TestLoadRejects(t *testing.T) {
+	const tactics = `"tactics": [{"id": "TA0002", "slug": "execution", "name": "Execution"}]`
+	tests := map[string]string{
+		"invalid JSON":         `{`,
+		"invalid ID":           `{` + tactics + `, "techniques": [{"id": "X1059", "name": "Bad", "tactics": ["execution"]}]}`,
+		"duplicated ID":        `{` + tactics + `, "techniques": [{"id": "T1059", "name": "A", "tactics": ["execution"]}, {"id": "T1059", "name": "B", "tactics": ["execution"]}]}`,
+		"unknown tactic":       `{` + tactics + `, "techniques": [{"id": "T1059", "name": "A", "tactics": ["impact"]}]}`,
+		"missing tactics":      `{` + tactics + `, "techniques": [{"id": "T1059", "name": "A", "tactics": []}]}`,
+		"orphan sub-technique": `{` + tactics + `, "techniques": [{"id": "T1059.001", "name": "A", "tactics": ["execution"]}]}`,
+		"duplicated tactic":    `{"tactics": [{"slug": "execution"}, {"slug": "execution"}], "techniques": []}`,
+	}
+	version := Version()
+	for name, data := range tests {
+		if err := load([]byte(data)); err == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+	if Version() != version {
+		t.Error("a rejected taxonomy replaced the bundled one")
+	}
+}
@@ -96,7 +96,7 @@ func createReports(demons, networkAdmins []models.User) []models.Report {
 		victimIdx   int
 		title       string
 		description string
-		status      string
+		status      models.ReportStatus
 	}{
 		{0, 0, "Initial Contact", "Successfully approached target AdminJohn during lunch break", "completed"},
 		{0, 1, "Hypnosis Progress", "TechSarah showing signs of susceptibility to mind control", "in_progress"},
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"andrei-api/models"
+
+	"gorm.io/gorm"
+)
+
+// duplicateAppeal matches pending or accepted appeals of a punishment that already has an older one.
+const duplicateAppeal = `appeals.deleted_at IS NULL AND appeals.status <> 'rejected' AND EXISTS (
+	SELECT 1 FROM appeals older
+	WHERE older.reward_id = appeals.reward_id AND older.id < appeals.id
+		AND older.deleted_at IS NULL AND older.status <> 'rejected')`
+
+// dedupeAppeals soft-deletes duplicate open appeals, keeping the oldest, so the unique index on open
+// appeals can be created on databases that predate it. A duplicate that was accepted reversed the
+// punishment a second time, so its reversal is soft-deleted along with it.
+func dedupeAppeals(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&models.Appeal{}) {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`UPDATE rewards SET deleted_at = NOW()
+			WHERE deleted_at IS NULL AND id IN (
+				SELECT reversal_id FROM appeals WHERE reversal_id IS NOT NULL AND ` + duplicateAppeal + `)`).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`UPDATE appeals SET deleted_at = NOW() WHERE ` + duplicateAppeal).Error
+	})
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"andrei-api/models"
+
+	"gorm.io/gorm"
+)
+
+// dedupeAssignments soft-deletes duplicate active demon-victim assignments, keeping the oldest,
+// so the unique index on active assignments can be created on databases that predate it.
+func dedupeAssignments(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&models.DemonVictim{}) {
+		return nil
+	}
+	hasStatus := db.Migrator().HasColumn(&models.DemonVictim{}, "status")
+
+	active := func(table string) string {
+		condition := table + ".deleted_at IS NULL"
+		if hasStatus {
+			condition += " AND " + table + ".status <> 'abandoned'"
+		}
+		return condition
+	}
+
+	return db.Exec(`UPDATE demon_victims SET deleted_at = NOW()
+		WHERE ` + active("demon_victims") + ` AND EXISTS (
+			SELECT 1 FROM demon_victims older
+			WHERE older.demon_id = demon_victims.demon_id AND older.victim_id = demon_victims.victim_id
+				AND older.id < demon_victims.id AND ` + active("older") + `)`).Error
+}
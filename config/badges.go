@@ -0,0 +1,21 @@
+package config
+
+import (
+	"andrei-api/models"
+
+	"gorm.io/gorm"
+)
+
+// dropBadgeNameConstraint removes the table-wide unique constraint on badge names, which kept the
+// names of deleted badges reserved. Names are now unique among live badges only.
+func dropBadgeNameConstraint(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&models.Badge{}) {
+		return nil
+	}
+	for _, constraint := range []string{"uni_badges_name", "badges_name_key"} {
+		if err := db.Exec("ALTER TABLE badges DROP CONSTRAINT IF EXISTS " + constraint).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -22,21 +22,72 @@ func ConnectDatabase() {
 	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		dbHost, dbPort, dbUser, dbPassword, dbName)
 
-	database, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	database, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
+	if err := dedupeAssignments(database); err != nil {
+		log.Fatal("Failed to clean up duplicate victim assignments:", err)
+	}
+
+	if err := dedupeAppeals(database); err != nil {
+		log.Fatal("Failed to clean up duplicate appeals:", err)
+	}
+
+	if err := dropBadgeNameConstraint(database); err != nil {
+		log.Fatal("Failed to migrate badge names:", err)
+	}
+
+	if err := closeDuplicateSeasons(database); err != nil {
+		log.Fatal("Failed to clean up duplicate active seasons:", err)
+	}
+
+	if err := dropTemplateNameConstraint(database); err != nil {
+		log.Fatal("Failed to migrate report template names:", err)
+	}
+
+	if err := database.SetupJoinTable(&models.Report{}, "Assets", &models.ReportAsset{}); err != nil {
+		log.Fatal("Failed to set up report assets:", err)
+	}
+
 	err = database.AutoMigrate(
 		&models.User{},
 		&models.Post{},
 		&models.Report{},
 		&models.Reward{},
 		&models.DemonVictim{},
+		&models.Season{},
+		&models.SeasonStanding{},
+		&models.Badge{},
+		&models.DemonBadge{},
+		&models.Appeal{},
+		&models.PointTransfer{},
+		&models.ScoringFormula{},
+		&models.RankingSnapshot{},
+		&models.ReportStatusChange{},
+		&models.Attachment{},
+		&models.CustodyEntry{},
+		&models.ReportComment{},
+		&models.ReportRead{},
+		&models.Notification{},
+		&models.ReportTemplate{},
+		&models.ReportRevision{},
+		&models.VictimTransfer{},
+		&models.VictimDispute{},
+		&models.VictimOwnershipEvent{},
+		&models.Asset{},
+		&models.AssetService{},
+		&models.ScanImport{},
+		&models.ReportTechnique{},
+		&models.MissedDeadline{},
 	)
 	if err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
+	if err := migrateReportSearch(database); err != nil {
+		log.Fatal("Failed to migrate report search:", err)
+	}
 
 	DB = database
 	log.Println("Database connected and migrated successfully")
@@ -0,0 +1,22 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+)
+
+// EnvInt reads an integer setting from the environment, falling back to def when unset or invalid.
+func EnvInt(key string, def int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Invalid value %q for %s, using default %d", value, key, def)
+		return def
+	}
+	return parsed
+}
@@ -0,0 +1,24 @@
+package config
+
+import "gorm.io/gorm"
+
+// SearchLanguage is the Postgres text search configuration used to index and query reports.
+const SearchLanguage = "english"
+
+// migrateReportSearch adds the full-text search column of reports, kept in sync by Postgres as a
+// generated column (titles weigh more than descriptions), and its GIN index.
+func migrateReportSearch(db *gorm.DB) error {
+	statements := []string{
+		`ALTER TABLE reports ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+			setweight(to_tsvector('` + SearchLanguage + `', coalesce(title, '')), 'A') ||
+			setweight(to_tsvector('` + SearchLanguage + `', coalesce(description, '')), 'B')
+		) STORED`,
+		`CREATE INDEX IF NOT EXISTS idx_reports_search_vector ON reports USING GIN (search_vector)`,
+	}
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
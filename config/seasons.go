@@ -0,0 +1,23 @@
+package config
+
+import (
+	"andrei-api/models"
+
+	"gorm.io/gorm"
+)
+
+// closeDuplicateSeasons closes every active season but the most recent one, so the unique index on the
+// active season can be created on databases where two seasons were started concurrently. Their
+// standings are not archived; they overlap the season that stays active.
+func closeDuplicateSeasons(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&models.Season{}) {
+		return nil
+	}
+
+	return db.Exec(`UPDATE seasons SET status = ?, closed_at = NOW(), ends_at = COALESCE(ends_at, NOW())
+		WHERE status = ? AND deleted_at IS NULL AND EXISTS (
+			SELECT 1 FROM seasons newer
+			WHERE newer.status = ? AND newer.deleted_at IS NULL
+				AND (newer.starts_at, newer.id) > (seasons.starts_at, seasons.id))`,
+		models.SeasonStatusClosed, models.SeasonStatusActive, models.SeasonStatusActive).Error
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"log"
+	"os"
+
+	"andrei-api/storage"
+)
+
+var Storage storage.Storage
+
+func ConnectStorage() {
+	dir := os.Getenv("ATTACHMENTS_DIR")
+	if dir == "" {
+		dir = "uploads"
+	}
+
+	local, err := storage.NewLocalStorage(dir)
+	if err != nil {
+		log.Fatal("Failed to initialize attachment storage:", err)
+	}
+
+	Storage = local
+	log.Printf("Attachment storage ready at %s", dir)
+}
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"andrei-api/models"
+
+	"gorm.io/gorm"
+)
+
+// dropTemplateNameConstraint removes the table-wide unique constraint on report template names, which
+// kept the names of deleted templates reserved. Names are now unique among live templates only.
+func dropTemplateNameConstraint(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&models.ReportTemplate{}) {
+		return nil
+	}
+	for _, constraint := range []string{"uni_report_templates_name", "report_templates_name_key"} {
+		if err := db.Exec("ALTER TABLE report_templates DROP CONSTRAINT IF EXISTS " + constraint).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"andrei-api/config"
+	"andrei-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func metricValue(stats models.DemonStats, metric models.BadgeMetric) (int64, bool) {
+	switch metric {
+	case models.BadgeMetricReports:
+		return stats.ReportsCount, true
+	case models.BadgeMetricVictims:
+		return stats.VictimsCount, true
+	case models.BadgeMetricRewards:
+		return stats.RewardsCount, true
+	case models.BadgeMetricPunishments:
+		return stats.PunishmentsCount, true
+	case models.BadgeMetricPoints:
+		return stats.TotalPoints, true
+	}
+	return 0, false
+}
+
+// levelFor returns the level reached with the given points and the next one, if any.
+// Demons whose penalties put them below the first threshold still hold the first level.
+func levelFor(points int64) (models.Level, *models.Level) {
+	current := models.Levels[0]
+	for i := 1; i < len(models.Levels); i++ {
+		if points < models.Levels[i].MinPoints {
+			return current, &models.Levels[i]
+		}
+		current = models.Levels[i]
+	}
+	return current, nil
+}
+
+// levelProgress describes the level reached with the given points and the progress towards the next one.
+func levelProgress(points int64) gin.H {
+	current, next := levelFor(points)
+	progress := gin.H{
+		"level":        current,
+		"next_level":   next,
+		"total_points": points,
+	}
+	if next == nil {
+		progress["points_to_next_level"] = 0
+		progress["percent"] = 100.0
+		return progress
+	}
+
+	progress["points_to_next_level"] = next.MinPoints - points
+	percent := 0.0
+	if span := next.MinPoints - current.MinPoints; span > 0 {
+		percent = float64(points-current.MinPoints) / float64(span) * 100
+	}
+	progress["percent"] = math.Max(0, math.Min(percent, 100))
+	return progress
+}
+
+// awardBadges grants every badge whose criterion the demon currently meets.
+func awardBadges(demonID uint) error {
+	var badges []models.Badge
+	if err := config.DB.Find(&badges).Error; err != nil {
+		return err
+	}
+
+	stats := demonStats(demonID)
+	now := time.Now()
+	for _, badge := range badges {
+		value, ok := metricValue(stats, badge.Metric)
+		if !ok || value < badge.Threshold {
+			continue
+		}
+
+		award := models.DemonBadge{DemonID: demonID, BadgeID: badge.ID, AwardedAt: now}
+		if err := config.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&award).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refreshAchievements is called after activity that can change a demon's stats.
+// Failures are logged rather than failing the originating request.
+func refreshAchievements(demonID uint) {
+	if err := awardBadges(demonID); err != nil {
+		log.Printf("Failed to award badges to demon %d: %v", demonID, err)
+	}
+}
+
+func achievementsFor(demonID uint) (gin.H, error) {
+	if err := awardBadges(demonID); err != nil {
+		return nil, err
+	}
+
+	var earned []models.DemonBadge
+	if err := config.DB.Where("demon_id = ? AND badge_id IN (SELECT id FROM badges WHERE deleted_at IS NULL)", demonID).Preload("Badge").Order("awarded_at ASC").Find(&earned).Error; err != nil {
+		return nil, err
+	}
+
+	stats := demonStats(demonID)
+	return gin.H{"demon_id": demonID, "badges": earned, "progress": levelProgress(stats.TotalPoints)}, nil
+}
+
+func CreateBadge(c *gin.Context) {
+	var input models.BadgeCreate
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, ok := metricValue(models.DemonStats{}, input.Metric); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid metric. Use reports_count, victims_count, rewards_count, punishments_count or total_points"})
+		return
+	}
+
+	badge := models.Badge{
+		Name:        input.Name,
+		Description: input.Description,
+		Metric:      input.Metric,
+		Threshold:   input.Threshold,
+	}
+
+	if err := config.DB.Create(&badge).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			c.JSON(http.StatusConflict, gin.H{"error": "A badge with this name already exists"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create badge"})
+		return
+	}
+
+	// Award the new badge retroactively to demons who already qualify
+	var demons []models.User
+	if err := config.DB.Where("role = ?", models.RoleDemon).Find(&demons).Error; err == nil {
+		for _, demon := range demons {
+			refreshAchievements(demon.ID)
+		}
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"badge": badge})
+}
+
+func GetBadges(c *gin.Context) {
+	var badges []models.Badge
+	if err := config.DB.Order("metric, threshold").Find(&badges).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch badges"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"badges": badges, "levels": models.Levels})
+}
+
+func DeleteBadge(c *gin.Context) {
+	badgeID := c.Param("id")
+	id, err := strconv.ParseUint(badgeID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid badge ID"})
+		return
+	}
+
+	result := config.DB.Delete(&models.Badge{}, id)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete badge"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Badge not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Badge deleted successfully"})
+}
+
+func GetMyAchievements(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	achievements, err := achievementsFor(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch achievements"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"achievements": achievements})
+}
+
+func GetDemonAchievements(c *gin.Context) {
+	demonID := c.Param("id")
+	id, err := strconv.ParseUint(demonID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid demon ID"})
+		return
+	}
+
+	var demon models.User
+	if err := config.DB.Where("id = ? AND role = ?", id, models.RoleDemon).First(&demon).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Demon not found"})
+		return
+	}
+
+	achievements, err := achievementsFor(demon.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch achievements"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"achievements": achievements})
+}
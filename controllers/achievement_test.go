@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"andrei-api/models"
+)
+
+func TestLevelFor(t *testing.T) {
+	tests := []struct {
+		points  int64
+		current int
+		next    int
+	}{
+		{-50, 1, 2},
+		{0, 1, 2},
+		{99, 1, 2},
+		{100, 2, 3},
+		{2999, 5, 6},
+		{3000, 6, 0},
+		{10000, 6, 0},
+	}
+	for _, test := range tests {
+		current, next := levelFor(test.points)
+		if current.Number != test.current {
+			t.Errorf("levelFor(%d): level %d, want %d", test.points, current.Number, test.current)
+		}
+		if (next == nil) != (test.next == 0) || (next != nil && next.Number != test.next) {
+			t.Errorf("levelFor(%d): next level %v, want %d", test.points, next, test.next)
+		}
+	}
+}
+
+func TestLevelProgress(t *testing.T) {
+	tests := map[int64]float64{-50: 0, 0: 0, 50: 50, 100: 0, 3000: 100}
+	for points, want := range tests {
+		progress := levelProgress(points)
+		if percent := progress["percent"].(float64); percent != want {
+			t.Errorf("levelProgress(%d): percent %v, want %v", points, percent, want)
+		}
+		if _, err := json.Marshal(progress); err != nil {
+			t.Errorf("levelProgress(%d): %v", points, err)
+		}
+	}
+
+	if got := levelProgress(-50)["points_to_next_level"]; got != models.Levels[1].MinPoints+50 {
+		t.Errorf("levelProgress(-50): points to next level %v", got)
+	}
+}
@@ -0,0 +1,222 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"andrei-api/config"
+	"andrei-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// allocationWorkloadQuery loads every demon with their active assignments and the reports still awaiting their work.
+const allocationWorkloadQuery = `
+SELECT users.id AS demon_id,
+	users.username,
+	(SELECT COUNT(*) FROM demon_victims
+		WHERE demon_victims.demon_id = users.id AND demon_victims.status <> @abandoned AND demon_victims.deleted_at IS NULL) AS assignments,
+	(SELECT COUNT(*) FROM reports
+		WHERE reports.demon_id = users.id AND reports.status IN @open AND reports.deleted_at IS NULL) AS open_reports
+FROM users
+WHERE users.role = @demon AND users.deleted_at IS NULL
+ORDER BY users.id`
+
+// allocationCandidates loads the demons with their workload and a performance between 0 and 1
+// derived from their position in the ranking (1 for the leader).
+func allocationCandidates(db *gorm.DB) ([]models.AllocationCandidate, error) {
+	candidates := []models.AllocationCandidate{}
+	if err := db.Raw(allocationWorkloadQuery, map[string]interface{}{
+		"abandoned": models.AssignmentStatusAbandoned,
+		"open":      openReportStatuses,
+		"demon":     models.RoleDemon,
+	}).Scan(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	formula, err := loadScoringFormula()
+	if err != nil {
+		return nil, err
+	}
+	ranking, err := rankDemons(db, nil, formula, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	ranks := make(map[uint]int64, len(ranking))
+	for _, entry := range ranking {
+		ranks[entry.DemonID] = entry.Rank
+	}
+
+	for i := range candidates {
+		candidates[i].Allocated = []uint{}
+		if rank, ok := ranks[candidates[i].DemonID]; ok && len(ranking) > 0 {
+			candidates[i].Rank = rank
+			candidates[i].Performance = 1 - float64(rank-1)/float64(len(ranking))
+		}
+	}
+	return candidates, nil
+}
+
+// planAllocation hands each unassigned network admin, oldest first, to the demon with the lowest
+// workload relative to their performance: load / (0.5 + performance), so the best demon carries up to
+// three times the load of the worst. maxWorkload > 0 stops giving victims to demons with that many
+// active assignments.
+func planAllocation(candidates []models.AllocationCandidate, victims []models.User, maxWorkload int) ([]models.AllocationPair, []uint) {
+	pairs := []models.AllocationPair{}
+	unallocated := []uint{}
+
+	for _, victim := range victims {
+		best := -1
+		bestLoad := 0.0
+		for i := range candidates {
+			candidate := &candidates[i]
+			assignments := candidate.Assignments + int64(len(candidate.Allocated))
+			if maxWorkload > 0 && assignments >= int64(maxWorkload) {
+				continue
+			}
+			load := float64(assignments+candidate.OpenReports) / (0.5 + candidate.Performance)
+			if best == -1 || load < bestLoad {
+				best, bestLoad = i, load
+			}
+		}
+		if best == -1 {
+			unallocated = append(unallocated, victim.ID)
+			continue
+		}
+		candidates[best].Allocated = append(candidates[best].Allocated, victim.ID)
+		pairs = append(pairs, models.AllocationPair{DemonID: candidates[best].DemonID, VictimID: victim.ID})
+	}
+	return pairs, unallocated
+}
+
+// unassignedVictims selects the network admins no demon is actively targeting.
+func unassignedVictims(db *gorm.DB) *gorm.DB {
+	return db.Model(&models.User{}).Where("role = ? AND id NOT IN (?)", models.RoleNetworkAdmin,
+		activeAssignments(db.Session(&gorm.Session{NewDB: true})).Select("victim_id"))
+}
+
+// PreviewAllocation shows how the unassigned network admins would be spread across demons.
+// Nothing is assigned until the returned assignments are committed.
+func PreviewAllocation(c *gin.Context) {
+	maxWorkload := config.EnvInt("ALLOCATION_MAX_WORKLOAD", 0)
+	if value := c.Query("max_workload"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid max_workload"})
+			return
+		}
+		maxWorkload = parsed
+	}
+
+	var victims []models.User
+	if err := unassignedVictims(config.DB).Order("created_at ASC, id ASC").Find(&victims).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch unassigned network admins"})
+		return
+	}
+
+	candidates, err := allocationCandidates(config.DB)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute demon workloads"})
+		return
+	}
+
+	pairs, unallocated := planAllocation(candidates, victims, maxWorkload)
+
+	c.JSON(http.StatusOK, gin.H{
+		"assignments":  pairs,
+		"demons":       candidates,
+		"unallocated":  unallocated,
+		"max_workload": maxWorkload,
+	})
+}
+
+var errVictimTaken = errors.New("victim was assigned since the preview")
+
+// CommitAllocation assigns the victims of a preview. Pairs whose victim has been assigned in the
+// meantime, or whose demon or victim no longer exists, are skipped and reported.
+func CommitAllocation(c *gin.Context) {
+	var input models.AllocationCommit
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+
+	demonIDs := make([]uint, len(input.Assignments))
+	for i, pair := range input.Assignments {
+		demonIDs[i] = pair.DemonID
+	}
+	var demons []models.User
+	if err := config.DB.Where("id IN ? AND role = ?", demonIDs, models.RoleDemon).Find(&demons).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch demons"})
+		return
+	}
+	isDemon := make(map[uint]bool, len(demons))
+	for _, demon := range demons {
+		isDemon[demon.ID] = true
+	}
+
+	assigned := []models.DemonVictim{}
+	skipped := []gin.H{}
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		for _, pair := range input.Assignments {
+			if !isDemon[pair.DemonID] {
+				skipped = append(skipped, gin.H{"demon_id": pair.DemonID, "victim_id": pair.VictimID, "reason": "demon_not_found"})
+				continue
+			}
+
+			assignment, err := allocateVictim(tx, pair, user.ID)
+			switch {
+			case errors.Is(err, errVictimNotFound):
+				skipped = append(skipped, gin.H{"demon_id": pair.DemonID, "victim_id": pair.VictimID, "reason": "victim_not_found"})
+			case errors.Is(err, errVictimTaken):
+				skipped = append(skipped, gin.H{"demon_id": pair.DemonID, "victim_id": pair.VictimID, "reason": "already_assigned"})
+			case err != nil:
+				return err
+			default:
+				assigned = append(assigned, assignment)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to allocate victims"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"assigned": assigned, "skipped": skipped})
+}
+
+// allocateVictim assigns a victim nobody is targeting to the demon and tells them about it.
+func allocateVictim(tx *gorm.DB, pair models.AllocationPair, actorID uint) (models.DemonVictim, error) {
+	var victim models.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+		Where("id = ? AND role = ?", pair.VictimID, models.RoleNetworkAdmin).First(&victim).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.DemonVictim{}, errVictimNotFound
+		}
+		return models.DemonVictim{}, err
+	}
+
+	var active int64
+	if err := activeAssignments(tx).Where("victim_id = ?", pair.VictimID).Count(&active).Error; err != nil {
+		return models.DemonVictim{}, err
+	}
+	if active > 0 {
+		return models.DemonVictim{}, errVictimTaken
+	}
+
+	assignment, err := assignVictim(tx, pair.DemonID, pair.VictimID)
+	if err != nil {
+		return assignment, err
+	}
+	if err := recordOwnership(tx, assignment, models.OwnershipAssigned, actorID, nil, "Automatic allocation"); err != nil {
+		return assignment, err
+	}
+	message := "Andrei assigned you a new victim: " + assignment.Victim.Username
+	return assignment, notifyVictim(tx, pair.DemonID, message, &assignment.ID)
+}
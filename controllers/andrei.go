@@ -1,12 +1,15 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"andrei-api/config"
 	"andrei-api/models"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func GetAllUsers(c *gin.Context) {
@@ -60,6 +63,11 @@ func CreateReward(c *gin.Context) {
 		return
 	}
 
+	if input.Type != models.RewardTypeReward && input.Type != models.RewardTypePunishment {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid type. Use reward or punishment"})
+		return
+	}
+
 	var demon models.User
 	if err := config.DB.Where("id = ? AND role = ?", input.DemonID, models.RoleDemon).First(&demon).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Demon not found"})
@@ -79,6 +87,8 @@ func CreateReward(c *gin.Context) {
 		return
 	}
 
+	refreshAchievements(reward.DemonID)
+
 	c.JSON(http.StatusCreated, gin.H{"reward": reward})
 }
 
@@ -89,36 +99,75 @@ func GetPlatformStats(c *gin.Context) {
 	config.DB.Model(&models.User{}).Where("role = ?", models.RoleDemon).Count(&stats.TotalDemons)
 	config.DB.Model(&models.User{}).Where("role = ?", models.RoleNetworkAdmin).Count(&stats.TotalNetworkAdmins)
 	config.DB.Model(&models.Post{}).Count(&stats.TotalPosts)
-	config.DB.Model(&models.Report{}).Count(&stats.TotalReports)
+	config.DB.Model(&models.Report{}).Where("status NOT IN ?", uncountedReportStatuses).Count(&stats.TotalReports)
 
 	c.JSON(http.StatusOK, gin.H{"stats": stats})
 }
 
 func GetDemonRanking(c *gin.Context) {
-	var demons []models.User
-	if err := config.DB.Where("role = ?", models.RoleDemon).Find(&demons).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch demons"})
+	// Rankings are scoped to a season: the requested one, or the active one by default.
+	// ?season_id=all, or no season at all, ranks on all-time totals.
+	var season *models.Season
+	switch seasonParam := c.Query("season_id"); seasonParam {
+	case "all":
+	case "":
+		current, err := currentSeason()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch current season"})
+			return
+		}
+		season = current
+	default:
+		id, err := strconv.ParseUint(seasonParam, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID"})
+			return
+		}
+		if err := closeExpiredSeasons(); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to close expired seasons"})
+			return
+		}
+		season = &models.Season{}
+		if err := config.DB.First(season, id).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Season not found"})
+			return
+		}
+	}
+
+	formula, err := loadScoringFormula()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch scoring formula"})
 		return
 	}
 
-	var demonStats []models.DemonStats
-	for _, demon := range demons {
-		var stats models.DemonStats
-		stats.DemonID = demon.ID
+	page, limit, offset := pagination(c)
 
-		config.DB.Model(&models.User{}).Where("role = ? AND id IN (SELECT victim_id FROM reports WHERE demon_id = ?)", 
-			models.RoleNetworkAdmin, demon.ID).Count(&stats.VictimsCount)
-		
-		config.DB.Model(&models.Reward{}).Where("demon_id = ? AND type = ?", demon.ID, models.RewardTypeReward).Count(&stats.RewardsCount)
-		config.DB.Model(&models.Reward{}).Where("demon_id = ? AND type = ?", demon.ID, models.RewardTypePunishment).Count(&stats.PunishmentsCount)
-		config.DB.Model(&models.Report{}).Where("demon_id = ?", demon.ID).Count(&stats.ReportsCount)
-		
-		config.DB.Model(&models.Reward{}).Where("demon_id = ?", demon.ID).Select("COALESCE(SUM(points), 0)").Scan(&stats.TotalPoints)
+	var rankings []models.RankingEntry
+	if season != nil {
+		rankings, err = leaderboard(*season, limit, offset)
+	} else {
+		rankings, err = rankDemons(config.DB, nil, formula, limit, offset)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch demons"})
+		return
+	}
 
-		demonStats = append(demonStats, stats)
+	var total int64
+	if season != nil && season.Status == models.SeasonStatusClosed {
+		config.DB.Model(&models.SeasonStanding{}).Where("season_id = ?", season.ID).Count(&total)
+	} else {
+		config.DB.Model(&models.User{}).Where("role = ?", models.RoleDemon).Count(&total)
 	}
 
-	c.JSON(http.StatusOK, gin.H{"demon_rankings": demonStats})
+	c.JSON(http.StatusOK, gin.H{
+		"season":         season,
+		"formula":        formula,
+		"demon_rankings": rankings,
+		"page":           page,
+		"limit":          limit,
+		"total":          total,
+	})
 }
 
 func GetAllPosts(c *gin.Context) {
@@ -171,4 +220,191 @@ func CreateAndreiPost(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusCreated, gin.H{"post": post})
+}
+
+var errPunishmentAppealed = errors.New("punishment has a pending or accepted appeal")
+
+// UpdateReward edits a reward or punishment. Send If-Match with the reward's version to avoid overwriting
+// a concurrent change. Rewards keep positive points and punishments negative ones. The points of an appealed
+// punishment are frozen, since its reversal cancels the amount at the time of the appeal, and a reduction
+// cannot take the demon's available balance below zero (transfers may already have spent the points).
+func UpdateReward(c *gin.Context) {
+	rewardID := c.Param("id")
+	id, err := strconv.ParseUint(rewardID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reward ID"})
+		return
+	}
+
+	var input models.RewardUpdate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var reward models.Reward
+	if err := config.DB.First(&reward, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reward not found"})
+		return
+	}
+
+	// Reversals and transfers are bookkeeping entries owned by appeals and transfers
+	if reward.Type != models.RewardTypeReward && reward.Type != models.RewardTypePunishment {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Only rewards and punishments can be edited"})
+		return
+	}
+	if !ifMatch(c, reward.Version) {
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if input.Title != nil {
+		reward.Title = *input.Title
+		updates["title"] = reward.Title
+	}
+	if input.Description != nil {
+		reward.Description = *input.Description
+		updates["description"] = reward.Description
+	}
+	reduction := 0
+	if input.Points != nil {
+		switch {
+		case reward.Type == models.RewardTypeReward && *input.Points <= 0:
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Rewards must have positive points"})
+			return
+		case reward.Type == models.RewardTypePunishment && *input.Points >= 0:
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Punishments must have negative points"})
+			return
+		}
+		if *input.Points < reward.Points {
+			reduction = reward.Points - *input.Points
+		}
+		reward.Points = *input.Points
+		updates["points"] = reward.Points
+	}
+
+	if len(updates) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nothing to change"})
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if input.Points == nil {
+			return versionedUpdate(tx, &models.Reward{}, reward.ID, reward.Version, updates)
+		}
+
+		// Lock the demon, then the reward, as transfers and appeal acceptance do
+		if err := lockDemon(tx, reward.DemonID); err != nil {
+			return err
+		}
+		var current models.Reward
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, reward.ID).Error; err != nil {
+			return err
+		}
+		if current.Version != reward.Version {
+			return errStaleVersion
+		}
+
+		if reward.Type == models.RewardTypePunishment && current.Points != reward.Points {
+			var appeals int64
+			if err := tx.Model(&models.Appeal{}).Where("reward_id = ? AND status IN ?", reward.ID,
+				[]models.AppealStatus{models.AppealStatusPending, models.AppealStatusAccepted}).Count(&appeals).Error; err != nil {
+				return err
+			}
+			if appeals > 0 {
+				return errPunishmentAppealed
+			}
+		}
+
+		if reduction > 0 {
+			available, err := availablePoints(tx, reward.DemonID)
+			if err != nil {
+				return err
+			}
+			if available < int64(reduction) {
+				return errInsufficientPoints
+			}
+		}
+
+		return versionedUpdate(tx, &models.Reward{}, reward.ID, reward.Version, updates)
+	})
+	switch {
+	case errors.Is(err, errStaleVersion):
+		respondStaleVersion(c)
+		return
+	case errors.Is(err, errPunishmentAppealed):
+		c.JSON(http.StatusConflict, gin.H{"error": "The points of a punishment with a pending or accepted appeal cannot change"})
+		return
+	case errors.Is(err, errInsufficientPoints):
+		c.JSON(http.StatusConflict, gin.H{"error": "Reducing these points would leave the demon with a negative balance"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update reward"})
+		return
+	}
+	reward.Version++
+
+	if input.Points != nil {
+		refreshAchievements(reward.DemonID)
+	}
+
+	setETag(c, reward.Version)
+	c.JSON(http.StatusOK, gin.H{"reward": reward})
+}
+
+// UpdatePost edits a post. Send If-Match with the post's version to avoid overwriting a concurrent change.
+func UpdatePost(c *gin.Context) {
+	postID := c.Param("id")
+	id, err := strconv.ParseUint(postID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+
+	var input models.PostUpdate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var post models.Post
+	if err := config.DB.First(&post, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+	if !ifMatch(c, post.Version) {
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if input.Title != nil {
+		post.Title = *input.Title
+		updates["title"] = post.Title
+	}
+	if input.Body != nil {
+		post.Body = *input.Body
+		updates["body"] = post.Body
+	}
+	if input.Media != nil {
+		post.Media = *input.Media
+		updates["media"] = post.Media
+	}
+
+	if len(updates) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nothing to change"})
+		return
+	}
+
+	if err := versionedUpdate(config.DB, &models.Post{}, post.ID, post.Version, updates); err != nil {
+		if errors.Is(err, errStaleVersion) {
+			respondStaleVersion(c)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update post"})
+		return
+	}
+	post.Version++
+
+	setETag(c, post.Version)
+	c.JSON(http.StatusOK, gin.H{"post": post})
 }
\ No newline at end of file
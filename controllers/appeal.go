@@ -0,0 +1,191 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"andrei-api/config"
+	"andrei-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var errAppealResolved = errors.New("appeal already resolved")
+
+func CreateAppeal(c *gin.Context) {
+	var input models.AppealCreate
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+
+	var punishment models.Reward
+	if err := config.DB.Where("id = ? AND demon_id = ? AND type = ?", input.RewardID, user.ID, models.RewardTypePunishment).
+		First(&punishment).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Punishment not found"})
+		return
+	}
+
+	// A punishment can be appealed again after a rejection, but not while an appeal is open or once it was reversed
+	var open int64
+	config.DB.Model(&models.Appeal{}).Where("reward_id = ? AND status IN ?", punishment.ID,
+		[]models.AppealStatus{models.AppealStatusPending, models.AppealStatusAccepted}).Count(&open)
+	if open > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "This punishment already has a pending or accepted appeal"})
+		return
+	}
+
+	appeal := models.Appeal{
+		RewardID:      punishment.ID,
+		DemonID:       user.ID,
+		Justification: input.Justification,
+		Status:        models.AppealStatusPending,
+	}
+
+	if err := config.DB.Create(&appeal).Error; err != nil {
+		// The unique index on open appeals catches a concurrent appeal of the same punishment
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			c.JSON(http.StatusConflict, gin.H{"error": "This punishment already has a pending or accepted appeal"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create appeal"})
+		return
+	}
+
+	appeal.Reward = punishment
+	c.JSON(http.StatusCreated, gin.H{"appeal": appeal})
+}
+
+func GetMyAppeals(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	var appeals []models.Appeal
+	if err := config.DB.Where("demon_id = ?", user.ID).Preload("Reward").Preload("Reversal").
+		Order("created_at DESC").Find(&appeals).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch appeals"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"appeals": appeals})
+}
+
+func GetAppeals(c *gin.Context) {
+	query := config.DB.Preload("Reward").Preload("Demon").Preload("Reversal").Order("created_at DESC")
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if demonID := c.Query("demon_id"); demonID != "" {
+		query = query.Where("demon_id = ?", demonID)
+	}
+
+	var appeals []models.Appeal
+	if err := query.Find(&appeals).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch appeals"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"appeals": appeals})
+}
+
+// resolveAppeal locks a pending appeal and lets apply record the decision inside the same transaction.
+func resolveAppeal(c *gin.Context, apply func(tx *gorm.DB, appeal *models.Appeal) error) {
+	appealID := c.Param("id")
+	id, err := strconv.ParseUint(appealID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid appeal ID"})
+		return
+	}
+
+	var appeal models.Appeal
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&appeal, id).Error; err != nil {
+			return err
+		}
+		if appeal.Status != models.AppealStatusPending {
+			return errAppealResolved
+		}
+		return apply(tx, &appeal)
+	})
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Appeal not found"})
+		return
+	case errors.Is(err, errAppealResolved):
+		c.JSON(http.StatusConflict, gin.H{"error": "Appeal has already been resolved"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve appeal"})
+		return
+	}
+
+	config.DB.Preload("Reward").Preload("Reversal").First(&appeal, appeal.ID)
+	refreshAchievements(appeal.DemonID)
+
+	c.JSON(http.StatusOK, gin.H{"appeal": appeal})
+}
+
+func AcceptAppeal(c *gin.Context) {
+	var input models.AppealResolve
+	if err := c.ShouldBindJSON(&input); err != nil && c.Request.ContentLength > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+
+	resolveAppeal(c, func(tx *gorm.DB, appeal *models.Appeal) error {
+		var punishment models.Reward
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&punishment, appeal.RewardID).Error; err != nil {
+			return err
+		}
+
+		reversal := models.Reward{
+			DemonID:     appeal.DemonID,
+			Type:        models.RewardTypeReversal,
+			Title:       "Appeal accepted: " + punishment.Title,
+			Description: fmt.Sprintf("Reverses punishment #%d after appeal #%d", punishment.ID, appeal.ID),
+			Points:      -punishment.Points,
+		}
+		if err := tx.Create(&reversal).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		appeal.Status = models.AppealStatusAccepted
+		appeal.Reason = input.Reason
+		appeal.ResolvedByID = &user.ID
+		appeal.ResolvedAt = &now
+		appeal.ReversalID = &reversal.ID
+		return tx.Omit(clause.Associations).Save(appeal).Error
+	})
+}
+
+func RejectAppeal(c *gin.Context) {
+	var input struct {
+		Reason string `json:"reason" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+
+	resolveAppeal(c, func(tx *gorm.DB, appeal *models.Appeal) error {
+		now := time.Now()
+		appeal.Status = models.AppealStatusRejected
+		appeal.Reason = input.Reason
+		appeal.ResolvedByID = &user.ID
+		appeal.ResolvedAt = &now
+		return tx.Omit(clause.Associations).Save(appeal).Error
+	})
+}
@@ -0,0 +1,419 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"andrei-api/config"
+	"andrei-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var hostnamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?(\.[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?)*$`)
+
+// normalizeAddress validates an IP address or CIDR block and returns its canonical form.
+// Host bits of a CIDR block are cleared, so 10.0.0.5/24 becomes 10.0.0.0/24.
+func normalizeAddress(value string) (string, error) {
+	if strings.Contains(value, "/") {
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return "", err
+		}
+		return prefix.Masked().String(), nil
+	}
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return "", err
+	}
+	if addr.Zone() != "" {
+		return "", errors.New("zoned addresses are not supported")
+	}
+	return addr.Unmap().String(), nil
+}
+
+// buildAsset validates an asset input and returns the asset it describes, or the problems found.
+func buildAsset(input models.AssetInput) (models.Asset, []string) {
+	var problems []string
+	asset := models.Asset{
+		Hostname: strings.TrimSuffix(strings.ToLower(strings.TrimSpace(input.Hostname)), "."),
+		OS:       strings.TrimSpace(input.OS),
+		Notes:    input.Notes,
+		Services: []models.AssetService{},
+	}
+
+	if asset.Hostname != "" && (len(asset.Hostname) > 253 || !hostnamePattern.MatchString(asset.Hostname)) {
+		problems = append(problems, "hostname: must be a valid DNS name")
+	}
+	if address := strings.TrimSpace(input.Address); address != "" {
+		normalized, err := normalizeAddress(address)
+		if err != nil {
+			problems = append(problems, "address: must be an IPv4/IPv6 address or CIDR block")
+		}
+		asset.Address = normalized
+	}
+	if asset.Hostname == "" && asset.Address == "" && len(problems) == 0 {
+		problems = append(problems, "an asset needs a hostname or an address")
+	}
+
+	seen := make(map[string]bool)
+	for _, service := range input.Services {
+		protocol := strings.ToLower(service.Protocol)
+		if protocol == "" {
+			protocol = "tcp"
+		}
+		if service.Port < 1 || service.Port > 65535 {
+			problems = append(problems, fmt.Sprintf("port %d: must be between 1 and 65535", service.Port))
+			continue
+		}
+		if protocol != "tcp" && protocol != "udp" && protocol != "sctp" {
+			problems = append(problems, fmt.Sprintf("port %d: protocol must be tcp, udp or sctp", service.Port))
+			continue
+		}
+		key := fmt.Sprintf("%d/%s", service.Port, protocol)
+		if seen[key] {
+			problems = append(problems, key+": listed twice")
+			continue
+		}
+		seen[key] = true
+
+		asset.Services = append(asset.Services, models.AssetService{
+			Port:     service.Port,
+			Protocol: protocol,
+			Service:  strings.TrimSpace(service.Service),
+			Version:  strings.TrimSpace(service.Version),
+		})
+	}
+	return asset, problems
+}
+
+// containsPattern builds an ILIKE pattern matching values that contain text.
+func containsPattern(text string) string {
+	return "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(text) + "%"
+}
+
+// filterAssets applies the asset search filters: victim_id, hostname, address (an IP or CIDR block
+// overlapping the asset's address), os, and port, protocol and service on the asset's services.
+func filterAssets(c *gin.Context, query *gorm.DB) (*gorm.DB, bool) {
+	if value := c.Query("victim_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid victim_id"})
+			return query, false
+		}
+		query = query.Where("assets.victim_id = ?", id)
+	}
+	if hostname := c.Query("hostname"); hostname != "" {
+		query = query.Where("assets.hostname ILIKE ?", containsPattern(hostname))
+	}
+	if address := c.Query("address"); address != "" {
+		normalized, err := normalizeAddress(address)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid address, use an IP address or CIDR block"})
+			return query, false
+		}
+		query = query.Where("CAST(NULLIF(assets.address, '') AS inet) && CAST(? AS inet)", normalized)
+	}
+	if os := c.Query("os"); os != "" {
+		query = query.Where("assets.os ILIKE ?", containsPattern(os))
+	}
+
+	services := config.DB.Session(&gorm.Session{NewDB: true}).Table("asset_services").Select("1").
+		Where("asset_services.asset_id = assets.id")
+	filtered := false
+	if value := c.Query("port"); value != "" {
+		port, err := strconv.Atoi(value)
+		if err != nil || port < 1 || port > 65535 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid port, use a number between 1 and 65535"})
+			return query, false
+		}
+		services = services.Where("asset_services.port = ?", port)
+		filtered = true
+	}
+	if protocol := c.Query("protocol"); protocol != "" {
+		services = services.Where("asset_services.protocol = ?", strings.ToLower(protocol))
+		filtered = true
+	}
+	if service := c.Query("service"); service != "" {
+		services = services.Where("asset_services.service ILIKE ?", containsPattern(service))
+		filtered = true
+	}
+	if filtered {
+		query = query.Where("EXISTS (?)", services)
+	}
+	return query, true
+}
+
+// targetedVictims selects the IDs of the victims the demon is actively targeting.
+func targetedVictims(demonID uint) *gorm.DB {
+	return activeAssignments(config.DB).Where("demon_id = ?", demonID).Select("victim_id")
+}
+
+// assetForUser loads the asset in the :id parameter. Demons only see assets of victims they target.
+func assetForUser(c *gin.Context, user models.User) (models.Asset, bool) {
+	var asset models.Asset
+
+	assetID := c.Param("id")
+	id, err := strconv.ParseUint(assetID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid asset ID"})
+		return asset, false
+	}
+
+	query := config.DB.Where("id = ?", id)
+	if user.Role != models.RoleAndrei {
+		query = query.Where("victim_id IN (?)", targetedVictims(user.ID))
+	}
+	if err := query.Preload("Services").First(&asset).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Asset not found"})
+		return asset, false
+	}
+	return asset, true
+}
+
+// reportAssets loads the assets with the given IDs, which must all belong to the victim.
+func reportAssets(db *gorm.DB, victimID uint, ids []uint) ([]models.Asset, bool, error) {
+	unique := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		unique[id] = true
+	}
+
+	var assets []models.Asset
+	if len(unique) == 0 {
+		return assets, true, nil
+	}
+	if err := db.Where("id IN ? AND victim_id = ?", ids, victimID).Find(&assets).Error; err != nil {
+		return nil, false, err
+	}
+	return assets, len(assets) == len(unique), nil
+}
+
+// linkReportAssets replaces the assets a report is linked to.
+func linkReportAssets(tx *gorm.DB, reportID uint, assets []models.Asset) error {
+	if err := tx.Where("report_id = ?", reportID).Delete(&models.ReportAsset{}).Error; err != nil {
+		return err
+	}
+	if len(assets) == 0 {
+		return nil
+	}
+	links := make([]models.ReportAsset, len(assets))
+	for i, asset := range assets {
+		links[i] = models.ReportAsset{ReportID: reportID, AssetID: asset.ID}
+	}
+	return tx.Create(&links).Error
+}
+
+// CreateAsset records an asset of the demon's victim in the :id parameter.
+func CreateAsset(c *gin.Context) {
+	var input models.AssetInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	assignment, ok := myAssignment(c)
+	if !ok {
+		return
+	}
+
+	asset, problems := buildAsset(input)
+	if len(problems) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid asset", "details": problems})
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+	asset.VictimID = assignment.VictimID
+	asset.DiscoveredByID = user.ID
+
+	if err := config.DB.Create(&asset).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create asset"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"asset": asset})
+}
+
+// GetVictimAssets lists the assets of the network admin in the :id parameter. Demons must be targeting them.
+func GetVictimAssets(c *gin.Context) {
+	victimID := c.Param("id")
+	id, err := strconv.ParseUint(victimID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid victim ID"})
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+	if user.Role != models.RoleAndrei {
+		if _, err := activeAssignment(config.DB, user.ID, uint(id)); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "This network admin is not your victim"})
+			return
+		}
+	}
+
+	var assets []models.Asset
+	if err := config.DB.Where("victim_id = ?", id).Preload("Services", func(db *gorm.DB) *gorm.DB {
+		return db.Order("port ASC, protocol ASC")
+	}).Order("address ASC, hostname ASC").Find(&assets).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch assets"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"victim_id": id, "assets": assets})
+}
+
+// SearchAssets queries assets across victims. Demons only search the victims they target.
+func SearchAssets(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	query := config.DB.Model(&models.Asset{})
+	if user.Role != models.RoleAndrei {
+		query = query.Where("assets.victim_id IN (?)", targetedVictims(user.ID))
+	}
+	query, ok := filterAssets(c, query)
+	if !ok {
+		return
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search assets"})
+		return
+	}
+
+	page, limit, offset := pagination(c)
+	var assets []models.Asset
+	if err := query.Preload("Victim").Preload("Services").Order("assets.victim_id ASC, assets.id ASC").
+		Limit(limit).Offset(offset).Find(&assets).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search assets"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"assets": assets, "page": page, "limit": limit, "total": total})
+}
+
+// UpdateAsset replaces an asset's details and services.
+func UpdateAsset(c *gin.Context) {
+	var input models.AssetInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+
+	asset, ok := assetForUser(c, user)
+	if !ok {
+		return
+	}
+
+	updated, problems := buildAsset(input)
+	if len(problems) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid asset", "details": problems})
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Asset{}).Where("id = ?", asset.ID).Updates(map[string]interface{}{
+			"hostname": updated.Hostname,
+			"address":  updated.Address,
+			"os":       updated.OS,
+			"notes":    updated.Notes,
+		}).Error; err != nil {
+			return err
+		}
+		return replaceAssetServices(tx, asset.ID, updated.Services)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update asset"})
+		return
+	}
+
+	asset.Hostname, asset.Address, asset.OS, asset.Notes = updated.Hostname, updated.Address, updated.OS, updated.Notes
+	asset.Services = updated.Services
+	c.JSON(http.StatusOK, gin.H{"asset": asset})
+}
+
+// replaceAssetServices swaps an asset's services for the given ones.
+func replaceAssetServices(tx *gorm.DB, assetID uint, services []models.AssetService) error {
+	if err := tx.Where("asset_id = ?", assetID).Delete(&models.AssetService{}).Error; err != nil {
+		return err
+	}
+	if len(services) == 0 {
+		return nil
+	}
+	for i := range services {
+		services[i].ID = 0
+		services[i].AssetID = assetID
+	}
+	return tx.Create(&services).Error
+}
+
+func DeleteAsset(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	asset, ok := assetForUser(c, user)
+	if !ok {
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("asset_id = ?", asset.ID).Delete(&models.ReportAsset{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&asset).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete asset"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Asset deleted successfully"})
+}
+
+// SetReportAssets replaces the assets a demon's report is linked to.
+func SetReportAssets(c *gin.Context) {
+	var input models.ReportAssetsUpdate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+
+	report, ok := reportForUser(c, user)
+	if !ok {
+		return
+	}
+
+	assets, valid, err := reportAssets(config.DB, report.VictimID, input.AssetIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch assets"})
+		return
+	}
+	if !valid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Reports can only be linked to assets of their victim"})
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		// Lock the report so concurrent updates do not interleave their links
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.Report{}, report.ID).Error; err != nil {
+			return err
+		}
+		return linkReportAssets(tx, report.ID, assets)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to link assets"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"report_id": report.ID, "assets": assets})
+}
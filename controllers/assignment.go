@@ -0,0 +1,318 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"andrei-api/config"
+	"andrei-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	errUnknownAssignmentStatus = errors.New("unknown assignment status")
+	errAssignmentTransition    = errors.New("assignment transition not allowed")
+	errAssignmentChanged       = errors.New("assignment status changed concurrently")
+	errVictimNotFound          = errors.New("network admin not found")
+	errAlreadyAssigned         = errors.New("victim already assigned to this demon")
+	errVictimAtCapacity        = errors.New("victim already has the maximum number of demons")
+)
+
+// maxDemonsPerVictim is how many demons can target the same network admin at once,
+// from VICTIM_MAX_DEMONS (default 0, unlimited).
+func maxDemonsPerVictim() int {
+	return config.EnvInt("VICTIM_MAX_DEMONS", 0)
+}
+
+// assignmentTransitions lists the statuses each assignment status can move to. Abandoned is final:
+// targeting the victim again creates a new assignment.
+var assignmentTransitions = map[models.AssignmentStatus][]models.AssignmentStatus{
+	models.AssignmentStatusTargeted:    {models.AssignmentStatusCompromised, models.AssignmentStatusAbandoned},
+	models.AssignmentStatusCompromised: {models.AssignmentStatusAbandoned},
+}
+
+// activeAssignments selects the assignments that have not been abandoned.
+func activeAssignments(db *gorm.DB) *gorm.DB {
+	return db.Model(&models.DemonVictim{}).Where("demon_victims.status <> ?", models.AssignmentStatusAbandoned)
+}
+
+// activeAssignment loads the demon's active assignment on a victim.
+func activeAssignment(db *gorm.DB, demonID, victimID uint) (models.DemonVictim, error) {
+	var assignment models.DemonVictim
+	err := activeAssignments(db).Where("demon_id = ? AND victim_id = ?", demonID, victimID).First(&assignment).Error
+	return assignment, err
+}
+
+// assignVictim creates an active assignment of the victim to the demon. The victim row is locked so that
+// concurrent assignments of the same network admin are serialized and VICTIM_MAX_DEMONS holds;
+// the unique index on active assignments catches duplicates for the same demon.
+func assignVictim(tx *gorm.DB, demonID, victimID uint) (models.DemonVictim, error) {
+	var victim models.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND role = ?", victimID, models.RoleNetworkAdmin).First(&victim).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.DemonVictim{}, errVictimNotFound
+		}
+		return models.DemonVictim{}, err
+	}
+
+	if _, err := activeAssignment(tx, demonID, victimID); err == nil {
+		return models.DemonVictim{}, errAlreadyAssigned
+	}
+
+	if limit := maxDemonsPerVictim(); limit > 0 {
+		var demons int64
+		if err := activeAssignments(tx).Where("victim_id = ?", victimID).Count(&demons).Error; err != nil {
+			return models.DemonVictim{}, err
+		}
+		if demons >= int64(limit) {
+			return models.DemonVictim{}, errVictimAtCapacity
+		}
+	}
+
+	assignment := models.DemonVictim{
+		DemonID:  demonID,
+		VictimID: victimID,
+		Victim:   victim,
+		Status:   models.AssignmentStatusTargeted,
+	}
+	if err := tx.Omit(clause.Associations).Create(&assignment).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return models.DemonVictim{}, errAlreadyAssigned
+		}
+		return models.DemonVictim{}, err
+	}
+	return assignment, nil
+}
+
+// recordOwnership appends an event to the victim's ownership history.
+func recordOwnership(tx *gorm.DB, assignment models.DemonVictim, event models.OwnershipEventType, actorID uint, relatedDemonID *uint, note string) error {
+	return tx.Create(&models.VictimOwnershipEvent{
+		VictimID:       assignment.VictimID,
+		DemonID:        assignment.DemonID,
+		Event:          event,
+		AssignmentID:   assignment.ID,
+		RelatedDemonID: relatedDemonID,
+		ActorID:        actorID,
+		Note:           note,
+	}).Error
+}
+
+// releaseAssignment abandons an assignment and records why in the ownership history.
+func releaseAssignment(tx *gorm.DB, assignment *models.DemonVictim, event models.OwnershipEventType, actorID uint, relatedDemonID *uint, note string) error {
+	if err := changeAssignmentStatus(tx, assignment, models.AssignmentStatusAbandoned); err != nil {
+		return err
+	}
+	return recordOwnership(tx, *assignment, event, actorID, relatedDemonID, note)
+}
+
+// respondAssignError maps assignVictim errors to HTTP responses.
+func respondAssignError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, errVictimNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Network admin not found"})
+	case errors.Is(err, errAlreadyAssigned):
+		c.JSON(http.StatusConflict, gin.H{"error": "This network admin is already your victim", "code": "already_assigned"})
+	case errors.Is(err, errVictimAtCapacity):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":      fmt.Sprintf("This network admin is already targeted by the maximum of %d demons", maxDemonsPerVictim()),
+			"code":       "victim_at_capacity",
+			"max_demons": maxDemonsPerVictim(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assign victim"})
+	}
+}
+
+// changeAssignmentStatus applies a lifecycle transition. The update only succeeds if the status
+// has not changed since the assignment was read.
+func changeAssignmentStatus(tx *gorm.DB, assignment *models.DemonVictim, to models.AssignmentStatus) error {
+	switch to {
+	case models.AssignmentStatusTargeted, models.AssignmentStatusCompromised, models.AssignmentStatusAbandoned:
+	default:
+		return errUnknownAssignmentStatus
+	}
+
+	allowed := false
+	for _, candidate := range assignmentTransitions[assignment.Status] {
+		if candidate == to {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return errAssignmentTransition
+	}
+
+	updates := map[string]interface{}{"status": to}
+	if to == models.AssignmentStatusAbandoned {
+		updates["released_at"] = time.Now()
+	}
+
+	result := tx.Model(&models.DemonVictim{}).Where("id = ? AND status = ?", assignment.ID, assignment.Status).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errAssignmentChanged
+	}
+
+	assignment.Status = to
+	if releasedAt, ok := updates["released_at"].(time.Time); ok {
+		assignment.ReleasedAt = &releasedAt
+	}
+	return nil
+}
+
+// respondAssignmentError maps changeAssignmentStatus errors to HTTP responses.
+func respondAssignmentError(c *gin.Context, assignment models.DemonVictim, err error) {
+	switch {
+	case errors.Is(err, errUnknownAssignmentStatus):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status. Use targeted, compromised or abandoned"})
+	case errors.Is(err, errAssignmentTransition):
+		allowed := assignmentTransitions[assignment.Status]
+		if allowed == nil {
+			allowed = []models.AssignmentStatus{}
+		}
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "Status transition not allowed",
+			"status":  assignment.Status,
+			"allowed": allowed,
+		})
+	case errors.Is(err, errAssignmentChanged):
+		c.JSON(http.StatusConflict, gin.H{"error": "Assignment was changed by someone else, reload and retry"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update assignment"})
+	}
+}
+
+// myAssignment loads the current user's active assignment on the victim in the :id parameter.
+func myAssignment(c *gin.Context) (models.DemonVictim, bool) {
+	victimID := c.Param("id")
+	id, err := strconv.ParseUint(victimID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid victim ID"})
+		return models.DemonVictim{}, false
+	}
+
+	user := c.MustGet("user").(models.User)
+
+	assignment, err := activeAssignment(config.DB, user.ID, uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "This network admin is not your victim"})
+		return assignment, false
+	}
+	return assignment, true
+}
+
+func UpdateVictimStatus(c *gin.Context) {
+	var input models.AssignmentStatusUpdate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	assignment, ok := myAssignment(c)
+	if !ok {
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if input.Status == models.AssignmentStatusAbandoned {
+			return releaseAssignment(tx, &assignment, models.OwnershipReleased, user.ID, nil, "")
+		}
+		if err := changeAssignmentStatus(tx, &assignment, input.Status); err != nil {
+			return err
+		}
+		return recordOwnership(tx, assignment, models.OwnershipCompromised, user.ID, nil, "")
+	})
+	if err != nil {
+		respondAssignmentError(c, assignment, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"assignment": assignment})
+}
+
+// ReleaseVictim abandons the demon's assignment on a victim, who becomes available again.
+func ReleaseVictim(c *gin.Context) {
+	assignment, ok := myAssignment(c)
+	if !ok {
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		return releaseAssignment(tx, &assignment, models.OwnershipReleased, user.ID, nil, "")
+	})
+	if err != nil {
+		respondAssignmentError(c, assignment, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Victim released successfully", "assignment": assignment})
+}
+
+func GetAssignments(c *gin.Context) {
+	query := config.DB.Model(&models.DemonVictim{})
+	for _, param := range []string{"demon_id", "victim_id"} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param})
+			return
+		}
+		query = query.Where(param+" = ?", id)
+	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var assignments []models.DemonVictim
+	if err := query.Preload("Demon").Preload("Victim").Order("created_at DESC").Find(&assignments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch assignments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"assignments": assignments})
+}
+
+// UnassignVictim lets andrei abandon any active assignment.
+func UnassignVictim(c *gin.Context) {
+	assignmentID := c.Param("id")
+	id, err := strconv.ParseUint(assignmentID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	var assignment models.DemonVictim
+	if err := config.DB.First(&assignment, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Assignment not found"})
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		return releaseAssignment(tx, &assignment, models.OwnershipReleased, user.ID, nil, "Unassigned by andrei")
+	})
+	if err != nil {
+		respondAssignmentError(c, assignment, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Victim unassigned successfully", "assignment": assignment})
+}
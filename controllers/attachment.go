@@ -0,0 +1,311 @@
+package controllers
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"andrei-api/config"
+	"andrei-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const defaultAllowedAttachmentTypes = "image/png,image/jpeg,image/gif,application/pdf,text/plain,application/zip"
+
+// Upload limits come from ATTACHMENT_MAX_BYTES (default 10 MiB) and ATTACHMENT_ALLOWED_TYPES
+// (comma separated MIME types). The type is sniffed from the content, not trusted from the client.
+func attachmentAllowed(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	allowed := os.Getenv("ATTACHMENT_ALLOWED_TYPES")
+	if allowed == "" {
+		allowed = defaultAllowedAttachmentTypes
+	}
+	for _, candidate := range strings.Split(allowed, ",") {
+		if strings.TrimSpace(candidate) == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+func newStorageKey(reportID uint) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("reports/%d/%s", reportID, hex.EncodeToString(random)), nil
+}
+
+func custodyEntry(c *gin.Context, attachment models.Attachment, user models.User, action models.CustodyAction, hash string) models.CustodyEntry {
+	return models.CustodyEntry{
+		AttachmentID: attachment.ID,
+		UserID:       user.ID,
+		Action:       action,
+		SHA256:       hash,
+		Verified:     hash != "" && hash == attachment.SHA256,
+		IPAddress:    c.ClientIP(),
+		UserAgent:    c.Request.UserAgent(),
+	}
+}
+
+// recordCustody adds an entry to the attachment's chain of custody. Access must not happen unrecorded,
+// so callers fail the request when it returns an error.
+func recordCustody(db *gorm.DB, c *gin.Context, attachment models.Attachment, user models.User, action models.CustodyAction, hash string) error {
+	entry := custodyEntry(c, attachment, user, action, hash)
+	return db.Create(&entry).Error
+}
+
+// storedHash reads an attachment back from storage and returns the SHA-256 of its content.
+func storedHash(attachment models.Attachment) (string, error) {
+	content, err := config.Storage.Open(attachment.StorageKey)
+	if err != nil {
+		return "", err
+	}
+	defer content.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, content); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// reportForUser loads a report visible to the user: any report for andrei, only their own for a demon.
+func reportForUser(c *gin.Context, user models.User) (models.Report, bool) {
+	var report models.Report
+
+	reportID := c.Param("id")
+	id, err := strconv.ParseUint(reportID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report ID"})
+		return report, false
+	}
+
+	query := config.DB.Where("id = ?", id)
+	if user.Role != models.RoleAndrei {
+		query = query.Where("demon_id = ?", user.ID)
+	}
+	if err := query.First(&report).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Report not found"})
+		return report, false
+	}
+	return report, true
+}
+
+// attachmentForUser loads an attachment whose report is visible to the user.
+func attachmentForUser(c *gin.Context, user models.User) (models.Attachment, bool) {
+	var attachment models.Attachment
+
+	attachmentID := c.Param("id")
+	id, err := strconv.ParseUint(attachmentID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attachment ID"})
+		return attachment, false
+	}
+
+	query := config.DB.Where("attachments.id = ?", id)
+	if user.Role != models.RoleAndrei {
+		query = query.Joins("JOIN reports ON reports.id = attachments.report_id AND reports.deleted_at IS NULL").
+			Where("reports.demon_id = ?", user.ID)
+	}
+	if err := query.First(&attachment).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+		return attachment, false
+	}
+	return attachment, true
+}
+
+func UploadAttachment(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	report, ok := reportForUser(c, user)
+	if !ok {
+		return
+	}
+
+	maxBytes := int64(config.EnvInt("ATTACHMENT_MAX_BYTES", 10<<20))
+	// Leave room for the multipart envelope around the file
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes+1<<20)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Attachments cannot exceed %d bytes", maxBytes)})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A file is required in the \"file\" form field"})
+		return
+	}
+	if header.Size > maxBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Attachments cannot exceed %d bytes", maxBytes)})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read uploaded file"})
+		return
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read uploaded file"})
+		return
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	if !attachmentAllowed(contentType) {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "File type " + contentType + " is not allowed"})
+		return
+	}
+
+	key, err := newStorageKey(report.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store attachment"})
+		return
+	}
+
+	hasher := sha256.New()
+	content := io.TeeReader(io.MultiReader(bytes.NewReader(head), file), hasher)
+	size, err := config.Storage.Save(key, content)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store attachment"})
+		return
+	}
+
+	attachment := models.Attachment{
+		ReportID:     report.ID,
+		UploadedByID: user.ID,
+		FileName:     filepath.Base(header.Filename),
+		ContentType:  contentType,
+		Size:         size,
+		SHA256:       hex.EncodeToString(hasher.Sum(nil)),
+		StorageKey:   key,
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&attachment).Error; err != nil {
+			return err
+		}
+		return recordCustody(tx, c, attachment, user, models.CustodyActionUpload, attachment.SHA256)
+	})
+	if err != nil {
+		config.Storage.Delete(key)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save attachment"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"attachment": attachment})
+}
+
+func GetReportAttachments(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	report, ok := reportForUser(c, user)
+	if !ok {
+		return
+	}
+
+	var attachments []models.Attachment
+	if err := config.DB.Where("report_id = ?", report.ID).Order("created_at ASC").Find(&attachments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch attachments"})
+		return
+	}
+
+	if len(attachments) > 0 {
+		entries := make([]models.CustodyEntry, len(attachments))
+		for i, attachment := range attachments {
+			entries[i] = custodyEntry(c, attachment, user, models.CustodyActionList, "")
+		}
+		if err := config.DB.Create(&entries).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record chain of custody"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"attachments": attachments})
+}
+
+// DownloadAttachment hashes the stored file and records the observed hash in the chain of custody
+// before streaming it. Nothing is sent when the custody entry cannot be written.
+func DownloadAttachment(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	attachment, ok := attachmentForUser(c, user)
+	if !ok {
+		return
+	}
+
+	hash, err := storedHash(attachment)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open attachment"})
+		return
+	}
+	if hash != attachment.SHA256 {
+		log.Printf("Integrity check failed for attachment %d: expected %s, got %s", attachment.ID, attachment.SHA256, hash)
+	}
+	if err := recordCustody(config.DB, c, attachment, user, models.CustodyActionDownload, hash); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record chain of custody"})
+		return
+	}
+
+	content, err := config.Storage.Open(attachment.StorageKey)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open attachment"})
+		return
+	}
+	defer content.Close()
+
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	c.Header("Content-Type", attachment.ContentType)
+	c.Header("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	c.Header("X-Content-SHA256", attachment.SHA256)
+	c.Status(http.StatusOK)
+
+	if _, err := io.Copy(c.Writer, content); err != nil {
+		log.Printf("Failed to stream attachment %d: %v", attachment.ID, err)
+	}
+}
+
+func GetAttachmentCustody(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	attachment, ok := attachmentForUser(c, user)
+	if !ok {
+		return
+	}
+
+	if err := recordCustody(config.DB, c, attachment, user, models.CustodyActionView, ""); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record chain of custody"})
+		return
+	}
+
+	var entries []models.CustodyEntry
+	if err := config.DB.Where("attachment_id = ?", attachment.ID).Preload("User").Order("created_at ASC, id ASC").Find(&entries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch chain of custody"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"attachment": attachment, "custody": entries})
+}
@@ -0,0 +1,371 @@
+package controllers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"andrei-api/config"
+	"andrei-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm/clause"
+)
+
+var mentionPattern = regexp.MustCompile(`@([A-Za-z0-9_.-]+)`)
+
+// commentWindow is how long an author can edit or delete a comment, from COMMENT_EDIT_WINDOW_MINUTES (default 15).
+func commentWindow() time.Duration {
+	return time.Duration(config.EnvInt("COMMENT_EDIT_WINDOW_MINUTES", 15)) * time.Minute
+}
+
+// markReportRead moves the user's read marker of a report to now.
+func markReportRead(reportID, userID uint) {
+	read := models.ReportRead{ReportID: reportID, UserID: userID, LastReadAt: time.Now()}
+	err := config.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "report_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"last_read_at"}),
+	}).Create(&read).Error
+	if err != nil {
+		log.Printf("Failed to mark report %d as read for user %d: %v", reportID, userID, err)
+	}
+}
+
+// unreadComments counts, per report, the comments written by others since the user last read it.
+func unreadComments(userID uint, reportIDs []uint) map[uint]int64 {
+	counts := make(map[uint]int64, len(reportIDs))
+	if len(reportIDs) == 0 {
+		return counts
+	}
+
+	var rows []struct {
+		ReportID uint
+		Unread   int64
+	}
+	err := config.DB.Table("report_comments").
+		Select("report_comments.report_id, COUNT(*) AS unread").
+		Joins("LEFT JOIN report_reads ON report_reads.report_id = report_comments.report_id AND report_reads.user_id = ?", userID).
+		Where("report_comments.deleted_at IS NULL AND report_comments.author_id <> ? AND report_comments.report_id IN ?", userID, reportIDs).
+		Where("report_reads.last_read_at IS NULL OR report_comments.created_at > report_reads.last_read_at").
+		Group("report_comments.report_id").
+		Scan(&rows).Error
+	if err != nil {
+		log.Printf("Failed to count unread comments for user %d: %v", userID, err)
+		return counts
+	}
+
+	for _, row := range rows {
+		counts[row.ReportID] = row.Unread
+	}
+	return counts
+}
+
+func withUnreadComments(userID uint, reports []models.Report) {
+	ids := make([]uint, 0, len(reports))
+	for _, report := range reports {
+		ids = append(ids, report.ID)
+	}
+
+	counts := unreadComments(userID, ids)
+	for i := range reports {
+		reports[i].UnreadComments = counts[reports[i].ID]
+	}
+}
+
+// mentions lists the usernames mentioned in a comment body.
+func mentions(body string) map[string]bool {
+	usernames := map[string]bool{}
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		usernames[match[1]] = true
+	}
+	return usernames
+}
+
+// notifyMentions notifies the users mentioned in a comment who can see the report:
+// its demon and andrei. Other mentions are ignored, as are the mentions already in the
+// previous body of an edited comment, whose users were notified then.
+func notifyMentions(report models.Report, comment models.ReportComment, author models.User, previousBody string) {
+	already := mentions(previousBody)
+	usernames := []string{}
+	for username := range mentions(comment.Body) {
+		if !already[username] {
+			usernames = append(usernames, username)
+		}
+	}
+	if len(usernames) == 0 {
+		return
+	}
+
+	var mentioned []models.User
+	if err := config.DB.Where("username IN ?", usernames).Find(&mentioned).Error; err != nil {
+		log.Printf("Failed to resolve mentions in comment %d: %v", comment.ID, err)
+		return
+	}
+
+	for _, user := range mentioned {
+		if user.ID == author.ID || (user.ID != report.DemonID && user.Role != models.RoleAndrei) {
+			continue
+		}
+		notification := models.Notification{
+			UserID:    user.ID,
+			Type:      models.NotificationTypeMention,
+			Message:   fmt.Sprintf("%s mentioned you on report #%d", author.Username, report.ID),
+			ReportID:  &report.ID,
+			CommentID: &comment.ID,
+		}
+		if err := config.DB.Create(&notification).Error; err != nil {
+			log.Printf("Failed to notify user %d: %v", user.ID, err)
+		}
+	}
+}
+
+// commentThreads nests replies under their parent. Replies whose parent was deleted move to the top level.
+func commentThreads(comments []models.ReportComment) []models.ReportComment {
+	byID := make(map[uint]int, len(comments))
+	for i, comment := range comments {
+		byID[comment.ID] = i
+	}
+
+	children := make(map[uint][]uint)
+	var roots []uint
+	for _, comment := range comments {
+		if comment.ParentID != nil {
+			if _, ok := byID[*comment.ParentID]; ok {
+				children[*comment.ParentID] = append(children[*comment.ParentID], comment.ID)
+				continue
+			}
+		}
+		roots = append(roots, comment.ID)
+	}
+
+	var build func(id uint) models.ReportComment
+	build = func(id uint) models.ReportComment {
+		comment := comments[byID[id]]
+		for _, childID := range children[id] {
+			comment.Replies = append(comment.Replies, build(childID))
+		}
+		return comment
+	}
+
+	threads := []models.ReportComment{}
+	for _, id := range roots {
+		threads = append(threads, build(id))
+	}
+	return threads
+}
+
+// commentForUser loads a comment on a report visible to the user.
+func commentForUser(c *gin.Context, user models.User) (models.ReportComment, models.Report, bool) {
+	var comment models.ReportComment
+	var report models.Report
+
+	commentID := c.Param("id")
+	id, err := strconv.ParseUint(commentID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return comment, report, false
+	}
+
+	if err := config.DB.First(&comment, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return comment, report, false
+	}
+
+	query := config.DB.Where("id = ?", comment.ReportID)
+	if user.Role != models.RoleAndrei {
+		query = query.Where("demon_id = ?", user.ID)
+	}
+	if err := query.First(&report).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return comment, report, false
+	}
+	return comment, report, true
+}
+
+func GetReportComments(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	report, ok := reportForUser(c, user)
+	if !ok {
+		return
+	}
+
+	var comments []models.ReportComment
+	if err := config.DB.Where("report_id = ?", report.ID).Preload("Author").Order("created_at ASC, id ASC").Find(&comments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
+		return
+	}
+
+	markReportRead(report.ID, user.ID)
+
+	c.JSON(http.StatusOK, gin.H{"comments": commentThreads(comments)})
+}
+
+func CreateComment(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	report, ok := reportForUser(c, user)
+	if !ok {
+		return
+	}
+
+	var input models.CommentCreate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if input.ParentID != nil {
+		var parent models.ReportComment
+		if err := config.DB.Where("id = ? AND report_id = ?", *input.ParentID, report.ID).First(&parent).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Parent comment not found on this report"})
+			return
+		}
+	}
+
+	comment := models.ReportComment{
+		ReportID: report.ID,
+		AuthorID: user.ID,
+		ParentID: input.ParentID,
+		Body:     input.Body,
+	}
+
+	if err := config.DB.Create(&comment).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create comment"})
+		return
+	}
+
+	notifyMentions(report, comment, user, "")
+	markReportRead(report.ID, user.ID)
+
+	comment.Author = user
+	c.JSON(http.StatusCreated, gin.H{"comment": comment})
+}
+
+func UpdateComment(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	comment, report, ok := commentForUser(c, user)
+	if !ok {
+		return
+	}
+
+	var input models.CommentUpdate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if comment.AuthorID != user.ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only edit your own comments"})
+		return
+	}
+	if time.Since(comment.CreatedAt) > commentWindow() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "The edit window for this comment has closed"})
+		return
+	}
+
+	now := time.Now()
+	previousBody := comment.Body
+	comment.Body = input.Body
+	comment.EditedAt = &now
+	if err := config.DB.Model(&comment).Select("Body", "EditedAt").Updates(&comment).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update comment"})
+		return
+	}
+
+	notifyMentions(report, comment, user, previousBody)
+
+	c.JSON(http.StatusOK, gin.H{"comment": comment})
+}
+
+// DeleteComment lets authors delete their comments within the edit window; andrei can delete any comment.
+func DeleteComment(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	comment, _, ok := commentForUser(c, user)
+	if !ok {
+		return
+	}
+
+	if user.Role != models.RoleAndrei {
+		if comment.AuthorID != user.ID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You can only delete your own comments"})
+			return
+		}
+		if time.Since(comment.CreatedAt) > commentWindow() {
+			c.JSON(http.StatusForbidden, gin.H{"error": "The delete window for this comment has closed"})
+			return
+		}
+	}
+
+	if err := config.DB.Delete(&comment).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete comment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted successfully"})
+}
+
+func GetNotifications(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	query := config.DB.Where("user_id = ?", user.ID).Order("created_at DESC")
+	if c.Query("unread") == "true" {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var notifications []models.Notification
+	if err := query.Limit(maxPageSize).Find(&notifications).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
+		return
+	}
+
+	var unread int64
+	config.DB.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", user.ID).Count(&unread)
+
+	c.JSON(http.StatusOK, gin.H{"notifications": notifications, "unread": unread})
+}
+
+func MarkNotificationRead(c *gin.Context) {
+	notificationID := c.Param("id")
+	id, err := strconv.ParseUint(notificationID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+
+	var notification models.Notification
+	if err := config.DB.Where("id = ? AND user_id = ?", id, user.ID).First(&notification).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+		return
+	}
+
+	if notification.ReadAt == nil {
+		now := time.Now()
+		notification.ReadAt = &now
+		if err := config.DB.Model(&notification).Update("read_at", now).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"notification": notification})
+}
+
+func MarkAllNotificationsRead(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	if err := config.DB.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", user.ID).
+		Update("read_at", time.Now()).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "All notifications marked as read"})
+}
@@ -0,0 +1,388 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"andrei-api/config"
+	"andrei-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// openReportStatuses are the statuses in which a report still awaits work from its demon.
+// Drafts are not submitted work yet, so they neither go overdue nor weigh on the demon's workload.
+var openReportStatuses = []models.ReportStatus{
+	models.ReportStatusPending,
+	models.ReportStatusInProgress,
+	models.ReportStatusRejected,
+}
+
+// overdueReports selects the reports past their deadline that are still open.
+func overdueReports(now time.Time) *gorm.DB {
+	return config.DB.Model(&models.Report{}).
+		Where("reports.due_at IS NOT NULL AND reports.due_at < ? AND reports.status IN ?", now, openReportStatuses)
+}
+
+// overdueAssignments selects the victim assignments past their deadline without a report from the demon.
+func overdueAssignments(now time.Time) *gorm.DB {
+	return activeAssignments(config.DB).
+		Where("demon_victims.due_at IS NOT NULL AND demon_victims.due_at < ?", now).
+		Where(`NOT EXISTS (SELECT 1 FROM reports WHERE reports.demon_id = demon_victims.demon_id
+			AND reports.victim_id = demon_victims.victim_id AND reports.deleted_at IS NULL
+			AND reports.status NOT IN ? AND reports.created_at >= demon_victims.created_at)`, uncountedReportStatuses)
+}
+
+// applyOverduePenalty flags the row as overdue, records the miss and, when OVERDUE_PUNISHMENT_POINTS is set,
+// punishes the demon. The flag is only set once per deadline, so a missed deadline is never punished twice.
+// overdue_reward_id links the punishment of the current deadline; the miss keeps the link for good.
+func applyOverduePenalty(tx *gorm.DB, model interface{}, id, demonID uint, title string, miss models.MissedDeadline, notification models.Notification) (bool, error) {
+	now := time.Now()
+	result := tx.Model(model).Where("id = ? AND overdue_at IS NULL", id).Update("overdue_at", now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	if points := config.EnvInt("OVERDUE_PUNISHMENT_POINTS", 0); points > 0 {
+		punishment := models.Reward{
+			DemonID:     demonID,
+			Type:        models.RewardTypePunishment,
+			Title:       "Missed deadline: " + title,
+			Description: "Applied automatically when the deadline passed",
+			Points:      -points,
+		}
+		if err := tx.Create(&punishment).Error; err != nil {
+			return false, err
+		}
+		if err := tx.Model(model).Where("id = ?", id).Update("overdue_reward_id", punishment.ID).Error; err != nil {
+			return false, err
+		}
+		miss.RewardID = &punishment.ID
+	}
+
+	miss.DemonID = demonID
+	if err := tx.Create(&miss).Error; err != nil {
+		return false, err
+	}
+
+	notification.UserID = demonID
+	notification.Type = models.NotificationTypeOverdue
+	return true, tx.Create(&notification).Error
+}
+
+// flagOverdue flags every newly overdue report and assignment and returns how many were flagged.
+func flagOverdue() (int, error) {
+	now := time.Now()
+	flagged := 0
+	punished := make(map[uint]bool)
+
+	var reports []models.Report
+	if err := overdueReports(now).Where("overdue_at IS NULL").Find(&reports).Error; err != nil {
+		return flagged, err
+	}
+	for _, report := range reports {
+		notification := models.Notification{
+			Message:  fmt.Sprintf("Report #%d \"%s\" is overdue", report.ID, report.Title),
+			ReportID: &report.ID,
+		}
+		var applied bool
+		err := config.DB.Transaction(func(tx *gorm.DB) error {
+			var err error
+			miss := models.MissedDeadline{ReportID: &report.ID, DueAt: *report.DueAt}
+			applied, err = applyOverduePenalty(tx, &models.Report{}, report.ID, report.DemonID, report.Title, miss, notification)
+			return err
+		})
+		if err != nil {
+			return flagged, err
+		}
+		if applied {
+			flagged++
+			punished[report.DemonID] = true
+		}
+	}
+
+	var assignments []models.DemonVictim
+	if err := overdueAssignments(now).Where("overdue_at IS NULL").Preload("Victim").Find(&assignments).Error; err != nil {
+		return flagged, err
+	}
+	for _, assignment := range assignments {
+		title := "report on " + assignment.Victim.Username
+		notification := models.Notification{
+			Message:      fmt.Sprintf("Your report on %s is overdue", assignment.Victim.Username),
+			AssignmentID: &assignment.ID,
+		}
+		var applied bool
+		err := config.DB.Transaction(func(tx *gorm.DB) error {
+			var err error
+			miss := models.MissedDeadline{AssignmentID: &assignment.ID, DueAt: *assignment.DueAt}
+			applied, err = applyOverduePenalty(tx, &models.DemonVictim{}, assignment.ID, assignment.DemonID, title, miss, notification)
+			return err
+		})
+		if err != nil {
+			return flagged, err
+		}
+		if applied {
+			flagged++
+			punished[assignment.DemonID] = true
+		}
+	}
+
+	if config.EnvInt("OVERDUE_PUNISHMENT_POINTS", 0) > 0 {
+		for demonID := range punished {
+			refreshAchievements(demonID)
+		}
+	}
+	return flagged, nil
+}
+
+// StartOverdueChecks flags overdue work every OVERDUE_CHECK_INTERVAL_MINUTES minutes (default 15, 0 disables it).
+func StartOverdueChecks() {
+	minutes := config.EnvInt("OVERDUE_CHECK_INTERVAL_MINUTES", 15)
+	if minutes <= 0 {
+		log.Println("Overdue checks disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Duration(minutes) * time.Minute)
+		defer ticker.Stop()
+		for {
+			if flagged, err := flagOverdue(); err != nil {
+				log.Printf("Failed to check overdue work: %v", err)
+			} else if flagged > 0 {
+				log.Printf("Flagged %d overdue items", flagged)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// overdueWork lists the overdue reports and assignments, optionally for a single demon, grouped by demon.
+func overdueWork(demonID uint) ([]models.OverdueWork, error) {
+	now := time.Now()
+
+	reportQuery := overdueReports(now).Preload("Victim").Order("reports.due_at ASC")
+	assignmentQuery := overdueAssignments(now).Preload("Victim").Order("demon_victims.due_at ASC")
+	if demonID != 0 {
+		reportQuery = reportQuery.Where("reports.demon_id = ?", demonID)
+		assignmentQuery = assignmentQuery.Where("demon_victims.demon_id = ?", demonID)
+	}
+
+	var reports []models.Report
+	if err := reportQuery.Find(&reports).Error; err != nil {
+		return nil, err
+	}
+	var assignments []models.DemonVictim
+	if err := assignmentQuery.Find(&assignments).Error; err != nil {
+		return nil, err
+	}
+
+	byDemon := make(map[uint]*models.OverdueWork)
+	workFor := func(id uint) *models.OverdueWork {
+		if work, ok := byDemon[id]; ok {
+			return work
+		}
+		work := &models.OverdueWork{DemonID: id, Reports: []models.Report{}, Assignments: []models.DemonVictim{}}
+		byDemon[id] = work
+		return work
+	}
+	for _, report := range reports {
+		work := workFor(report.DemonID)
+		work.Reports = append(work.Reports, report)
+	}
+	for _, assignment := range assignments {
+		work := workFor(assignment.DemonID)
+		work.Assignments = append(work.Assignments, assignment)
+	}
+
+	ids := make([]uint, 0, len(byDemon))
+	for id := range byDemon {
+		ids = append(ids, id)
+	}
+	var demons []models.User
+	if len(ids) > 0 {
+		if err := config.DB.Select("id", "username").Where("id IN ?", ids).Find(&demons).Error; err != nil {
+			return nil, err
+		}
+	}
+	for _, demon := range demons {
+		byDemon[demon.ID].Username = demon.Username
+	}
+
+	result := make([]models.OverdueWork, 0, len(byDemon))
+	for _, work := range byDemon {
+		result = append(result, *work)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return len(result[i].Reports)+len(result[i].Assignments) > len(result[j].Reports)+len(result[j].Assignments)
+	})
+	return result, nil
+}
+
+func SetReportDueDate(c *gin.Context) {
+	reportID := c.Param("id")
+	id, err := strconv.ParseUint(reportID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report ID"})
+		return
+	}
+
+	var input models.DeadlineUpdate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var report models.Report
+	if err := config.DB.First(&report, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Report not found"})
+		return
+	}
+
+	if !ifMatch(c, report.Version) {
+		return
+	}
+
+	// A new deadline starts over: the report can be flagged and punished again if it is missed.
+	// Earlier misses and their punishments stay in the missed deadlines.
+	updates := map[string]interface{}{"due_at": input.DueAt, "overdue_at": nil, "overdue_reward_id": nil}
+	if err := versionedUpdate(config.DB, &models.Report{}, report.ID, report.Version, updates); err != nil {
+		if errors.Is(err, errStaleVersion) {
+			respondStaleVersion(c)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update due date"})
+		return
+	}
+	report.DueAt = input.DueAt
+	report.OverdueAt = nil
+	report.OverdueRewardID = nil
+	report.Version++
+
+	setETag(c, report.Version)
+	c.JSON(http.StatusOK, gin.H{"report": report})
+}
+
+func SetAssignmentDueDate(c *gin.Context) {
+	assignmentID := c.Param("id")
+	id, err := strconv.ParseUint(assignmentID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	var input models.DeadlineUpdate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var assignment models.DemonVictim
+	if err := config.DB.First(&assignment, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Assignment not found"})
+		return
+	}
+	// An abandoned assignment can never be reported on, so its deadline could never be met
+	if assignment.Status == models.AssignmentStatusAbandoned {
+		c.JSON(http.StatusConflict, gin.H{"error": "Assignment is no longer active", "status": assignment.Status})
+		return
+	}
+
+	// As for reports, a new deadline starts over; earlier misses stay in the missed deadlines
+	assignment.DueAt = input.DueAt
+	assignment.OverdueAt = nil
+	assignment.OverdueRewardID = nil
+	result := config.DB.Model(&assignment).Where("status <> ?", models.AssignmentStatusAbandoned).
+		Select("DueAt", "OverdueAt", "OverdueRewardID").Updates(&assignment)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update due date"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Assignment is no longer active", "status": models.AssignmentStatusAbandoned})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"assignment": assignment})
+}
+
+// GetMissedDeadlines lists every missed deadline with its punishment, newest first.
+func GetMissedDeadlines(c *gin.Context) {
+	query := config.DB.Model(&models.MissedDeadline{})
+	for _, param := range []string{"demon_id", "report_id", "assignment_id"} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param})
+			return
+		}
+		query = query.Where(param+" = ?", id)
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch missed deadlines"})
+		return
+	}
+
+	page, limit, offset := pagination(c)
+	var misses []models.MissedDeadline
+	if err := query.Preload("Reward").Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&misses).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch missed deadlines"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"missed_deadlines": misses, "page": page, "limit": limit, "total": total})
+}
+
+func GetOverdueWork(c *gin.Context) {
+	var demonID uint
+	if value := c.Query("demon_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid demon_id"})
+			return
+		}
+		demonID = uint(id)
+	}
+
+	work, err := overdueWork(demonID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch overdue work"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"overdue": work})
+}
+
+func GetMyOverdueWork(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	work, err := overdueWork(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch overdue work"})
+		return
+	}
+
+	reports := []models.Report{}
+	assignments := []models.DemonVictim{}
+	if len(work) > 0 {
+		reports = work[0].Reports
+		assignments = work[0].Assignments
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reports": reports, "assignments": assignments})
+}
@@ -8,6 +8,7 @@ import (
 	"andrei-api/models"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 func GetAvailableNetworkAdmins(c *gin.Context) {
@@ -15,8 +16,13 @@ func GetAvailableNetworkAdmins(c *gin.Context) {
 
 	// Obtener todos los network admins que no son víctimas de este demonio
 	var networkAdmins []models.User
-	if err := config.DB.Where("role = ? AND id NOT IN (SELECT victim_id FROM demon_victims WHERE demon_id = ?)",
-		models.RoleNetworkAdmin, user.ID).Find(&networkAdmins).Error; err != nil {
+	query := config.DB.Where("role = ? AND id NOT IN (SELECT victim_id FROM demon_victims WHERE demon_id = ? AND status <> ? AND deleted_at IS NULL)",
+		models.RoleNetworkAdmin, user.ID, models.AssignmentStatusAbandoned)
+	if limit := maxDemonsPerVictim(); limit > 0 {
+		query = query.Where("id NOT IN (SELECT victim_id FROM demon_victims WHERE status <> ? AND deleted_at IS NULL GROUP BY victim_id HAVING COUNT(*) >= ?)",
+			models.AssignmentStatusAbandoned, limit)
+	}
+	if err := query.Find(&networkAdmins).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch available network admins"})
 		return
 	}
@@ -45,33 +51,24 @@ func AssignVictim(c *gin.Context) {
 
 	user := c.MustGet("user").(models.User)
 
-	// Verificar que la víctima existe y es network admin
-	var victim models.User
-	if err := config.DB.Where("id = ? AND role = ?", input.VictimID, models.RoleNetworkAdmin).First(&victim).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Network admin not found"})
-		return
-	}
-
-	// Verificar que no sea ya víctima de este demonio
-	var existingRelation models.DemonVictim
-	if err := config.DB.Where("demon_id = ? AND victim_id = ?", user.ID, input.VictimID).First(&existingRelation).Error; err == nil {
-		c.JSON(http.StatusConflict, gin.H{"error": "This network admin is already your victim"})
-		return
-	}
-
 	// Crear la relación demonio-víctima
-	demonVictim := models.DemonVictim{
-		DemonID:  user.ID,
-		VictimID: input.VictimID,
-	}
-
-	if err := config.DB.Create(&demonVictim).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assign victim"})
+	var demonVictim models.DemonVictim
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if demonVictim, err = assignVictim(tx, user.ID, input.VictimID); err != nil {
+			return err
+		}
+		return recordOwnership(tx, demonVictim, models.OwnershipAssigned, user.ID, nil, "")
+	})
+	if err != nil {
+		respondAssignError(c, err)
 		return
 	}
+	victim := demonVictim.Victim
 
 	c.JSON(http.StatusCreated, gin.H{
-		"message": "Victim assigned successfully",
+		"message":       "Victim assigned successfully",
+		"assignment_id": demonVictim.ID,
 		"victim": gin.H{
 			"id":       victim.ID,
 			"username": victim.Username,
@@ -95,47 +92,132 @@ func CreateReport(c *gin.Context) {
 		return
 	}
 
+	if input.TemplateID != nil {
+		var template models.ReportTemplate
+		if err := config.DB.First(&template, *input.TemplateID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+			return
+		}
+		if problems := validateReportData(template.Schema, input.Data); len(problems) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Report data does not match the template", "details": problems})
+			return
+		}
+	} else if len(input.Data) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Structured data requires a template_id"})
+		return
+	}
+
 	user := c.MustGet("user").(models.User)
 
+	// Reports are filed on an active assignment
+	assignment, err := activeAssignment(config.DB, user.ID, input.VictimID)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Assign this victim before reporting on them"})
+		return
+	}
+
+	assets, valid, err := reportAssets(config.DB, input.VictimID, input.AssetIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch assets"})
+		return
+	}
+	if !valid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Reports can only be linked to assets of their victim"})
+		return
+	}
+
+	techniques, unknown := resolveTechniques(input.TechniqueIDs)
+	if len(unknown) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown techniques", "techniques": unknown})
+		return
+	}
+
 	report := models.Report{
-		DemonID:     user.ID,
-		VictimID:    input.VictimID,
-		Title:       input.Title,
-		Description: input.Description,
-		Status:      "pending",
+		DemonID:      user.ID,
+		VictimID:     input.VictimID,
+		AssignmentID: &assignment.ID,
+		Title:        input.Title,
+		Description:  input.Description,
+		Status:       models.ReportStatusPending,
+		TemplateID:   input.TemplateID,
+		Data:         input.Data,
+	}
+	if err := applyCVSS(&report, input.CVSSVector); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid CVSS vector: " + err.Error()})
+		return
 	}
 
-	if err := config.DB.Create(&report).Error; err != nil {
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&report).Error; err != nil {
+			return err
+		}
+		if err := linkReportAssets(tx, report.ID, assets); err != nil {
+			return err
+		}
+		tags, err := linkReportTechniques(tx, report.ID, techniques)
+		if err != nil {
+			return err
+		}
+		report.Techniques = tags
+		if _, err := recordRevision(tx, report, user.ID); err != nil {
+			return err
+		}
+		return recordReportStatus(tx, report.ID, "", report.Status, user.ID, "Report created")
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create report"})
 		return
 	}
 
+	refreshAchievements(user.ID)
+
+	report.Assets = assets
 	c.JSON(http.StatusCreated, gin.H{"report": report})
 }
 
-func GetMyStats(c *gin.Context) {
-	user := c.MustGet("user").(models.User)
-
+func demonStats(demonID uint) models.DemonStats {
 	var stats models.DemonStats
-	stats.DemonID = user.ID
+	stats.DemonID = demonID
+
+	// Drafts and discarded drafts are not submitted work and do not count
+	config.DB.Model(&models.User{}).Where("role = ? AND id IN (SELECT victim_id FROM reports WHERE demon_id = ? AND status NOT IN ?)",
+		models.RoleNetworkAdmin, demonID, uncountedReportStatuses).Count(&stats.VictimsCount)
+
+	config.DB.Model(&models.Reward{}).Where("demon_id = ? AND type = ?", demonID, models.RewardTypeReward).Count(&stats.RewardsCount)
+	config.DB.Model(&models.Reward{}).Where("demon_id = ? AND type = ?", demonID, models.RewardTypePunishment).Count(&stats.PunishmentsCount)
+	config.DB.Model(&models.Report{}).Where("demon_id = ? AND status NOT IN ?", demonID, uncountedReportStatuses).Count(&stats.ReportsCount)
 
-	config.DB.Model(&models.User{}).Where("role = ? AND id IN (SELECT victim_id FROM reports WHERE demon_id = ?)",
-		models.RoleNetworkAdmin, user.ID).Count(&stats.VictimsCount)
+	config.DB.Model(&models.Reward{}).Where("demon_id = ?", demonID).Select("COALESCE(SUM(points), 0)").Scan(&stats.TotalPoints)
+
+	config.DB.Model(&models.PointTransfer{}).Where("from_demon_id = ? AND status = ?", demonID, models.TransferStatusCompleted).
+		Select("COALESCE(SUM(amount), 0)").Scan(&stats.PointsSent)
+	config.DB.Model(&models.PointTransfer{}).Where("from_demon_id = ? AND status = ?", demonID, models.TransferStatusCompleted).
+		Select("COALESCE(SUM(fee), 0)").Scan(&stats.TransferFees)
+	config.DB.Model(&models.PointTransfer{}).Where("to_demon_id = ? AND status = ?", demonID, models.TransferStatusCompleted).
+		Select("COALESCE(SUM(amount), 0)").Scan(&stats.PointsReceived)
+
+	stats.SeverityCounts, _ = severityCounts(config.DB, demonID)
+
+	return stats
+}
+
+func GetMyStats(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
 
-	config.DB.Model(&models.Reward{}).Where("demon_id = ? AND type = ?", user.ID, models.RewardTypeReward).Count(&stats.RewardsCount)
-	config.DB.Model(&models.Reward{}).Where("demon_id = ? AND type = ?", user.ID, models.RewardTypePunishment).Count(&stats.PunishmentsCount)
-	config.DB.Model(&models.Report{}).Where("demon_id = ?", user.ID).Count(&stats.ReportsCount)
+	stats := demonStats(user.ID)
 
-	config.DB.Model(&models.Reward{}).Where("demon_id = ?", user.ID).Select("COALESCE(SUM(points), 0)").Scan(&stats.TotalPoints)
+	var transfers []models.PointTransfer
+	config.DB.Where("from_demon_id = ? OR to_demon_id = ?", user.ID, user.ID).
+		Order("created_at DESC").Limit(10).Find(&transfers)
 
-	c.JSON(http.StatusOK, gin.H{"stats": stats})
+	c.JSON(http.StatusOK, gin.H{"stats": stats, "recent_transfers": transfers})
 }
 
 func GetMyVictims(c *gin.Context) {
 	user := c.MustGet("user").(models.User)
 
 	var demonVictims []models.DemonVictim
-	if err := config.DB.Where("demon_id = ?", user.ID).Preload("Victim").Find(&demonVictims).Error; err != nil {
+	if err := activeAssignments(config.DB).Where("demon_id = ?", user.ID).Preload("Victim").Find(&demonVictims).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch victims"})
 		return
 	}
@@ -146,17 +228,37 @@ func GetMyVictims(c *gin.Context) {
 		victims = append(victims, dv.Victim)
 	}
 
-	c.JSON(http.StatusOK, gin.H{"victims": victims})
+	c.JSON(http.StatusOK, gin.H{"victims": victims, "assignments": demonVictims})
 }
 
 func GetMyReports(c *gin.Context) {
 	user := c.MustGet("user").(models.User)
 
+	query, ok := filterReportSeverity(c, config.DB.Where("reports.demon_id = ?", user.ID))
+	if !ok {
+		return
+	}
+	query, ok = filterReportTechnique(c, query)
+	if !ok {
+		return
+	}
+	query, ok = filterReportData(c, query)
+	if !ok {
+		return
+	}
+	order, ok := reportOrder(c, "reports.created_at DESC")
+	if !ok {
+		return
+	}
+
 	var reports []models.Report
-	if err := config.DB.Where("demon_id = ?", user.ID).Preload("Victim").Find(&reports).Error; err != nil {
+	if err := query.Preload("Victim").Preload("Techniques").Order(order).Find(&reports).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reports"})
 		return
 	}
+	withReportTechniqueNames(reports)
+
+	withUnreadComments(user.ID, reports)
 
 	c.JSON(http.StatusOK, gin.H{"reports": reports})
 }
@@ -195,9 +297,7 @@ func UpdateReportStatus(c *gin.Context) {
 		return
 	}
 
-	var input struct {
-		Status string `json:"status" binding:"required"`
-	}
+	var input models.ReportStatusUpdate
 
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -211,12 +311,18 @@ func UpdateReportStatus(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Report not found"})
 		return
 	}
+	if !ifMatch(c, report.Version) {
+		return
+	}
 
-	report.Status = input.Status
-	if err := config.DB.Save(&report).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update report"})
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		return changeReportStatus(tx, &report, input.Status, user, input.Note)
+	})
+	if err != nil {
+		respondStatusError(c, report, user, err)
 		return
 	}
 
+	setETag(c, report.Version)
 	c.JSON(http.StatusOK, gin.H{"report": report})
 }
@@ -0,0 +1,201 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"andrei-api/config"
+	"andrei-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Exposure weights: points per active demon, per compromised assignment, per report, extra per
+// reviewed report and per public post (capped, since posting only reveals so much).
+const (
+	exposurePerDemon      = 10
+	exposurePerCompromise = 25
+	exposurePerReport     = 3
+	exposurePerReviewed   = 5
+	exposurePerPost       = 2
+	exposurePostsMax      = 10
+	exposureMax           = 100
+)
+
+// exposure scores a victim from their assignments, submitted reports and public posts.
+func exposure(assignments []models.DemonVictim, reports []models.Report, posts []models.Post) models.Exposure {
+	factors := map[string]int{"active_demons": 0, "compromised": 0, "reports": 0, "reviewed_reports": 0, "public_posts": 0}
+	for _, assignment := range assignments {
+		switch assignment.Status {
+		case models.AssignmentStatusCompromised:
+			factors["compromised"] += exposurePerCompromise
+			factors["active_demons"] += exposurePerDemon
+		case models.AssignmentStatusTargeted:
+			factors["active_demons"] += exposurePerDemon
+		}
+	}
+	for _, report := range reports {
+		if !countedReport(report.Status) {
+			continue
+		}
+		factors["reports"] += exposurePerReport
+		if report.Status == models.ReportStatusReviewed {
+			factors["reviewed_reports"] += exposurePerReviewed
+		}
+	}
+	factors["public_posts"] = len(posts) * exposurePerPost
+	if factors["public_posts"] > exposurePostsMax {
+		factors["public_posts"] = exposurePostsMax
+	}
+
+	score := 0
+	for _, points := range factors {
+		score += points
+	}
+	if score > exposureMax {
+		score = exposureMax
+	}
+
+	level := "critical"
+	switch {
+	case score < 25:
+		level = "low"
+	case score < 50:
+		level = "medium"
+	case score < 75:
+		level = "high"
+	}
+	return models.Exposure{Score: score, Level: level, Factors: factors}
+}
+
+// dossierTimeline merges ownership changes, report activity and public posts into one chronological list.
+func dossierTimeline(history []models.VictimOwnershipEvent, reports []models.Report, changes map[uint][]models.ReportStatusChange, posts []models.Post) []models.DossierEvent {
+	timeline := []models.DossierEvent{}
+	for i := range history {
+		event := &history[i]
+		description := fmt.Sprintf("%s: %s", event.Demon.Username, event.Event)
+		if event.Note != "" {
+			description += " (" + event.Note + ")"
+		}
+		timeline = append(timeline, models.DossierEvent{
+			At:           event.CreatedAt,
+			Type:         "assignment_" + string(event.Event),
+			Description:  description,
+			DemonID:      &event.DemonID,
+			AssignmentID: &event.AssignmentID,
+		})
+	}
+	for i := range reports {
+		report := &reports[i]
+		for _, change := range changes[report.ID] {
+			eventType, description := "report_status", fmt.Sprintf("Report \"%s\" moved from %s to %s", report.Title, change.FromStatus, change.ToStatus)
+			if change.FromStatus == "" {
+				eventType, description = "report_created", fmt.Sprintf("%s filed report \"%s\"", report.Demon.Username, report.Title)
+			}
+			timeline = append(timeline, models.DossierEvent{
+				At:          change.CreatedAt,
+				Type:        eventType,
+				Description: description,
+				DemonID:     &report.DemonID,
+				ReportID:    &report.ID,
+			})
+		}
+	}
+	for i := range posts {
+		post := &posts[i]
+		timeline = append(timeline, models.DossierEvent{
+			At:          post.CreatedAt,
+			Type:        "post",
+			Description: fmt.Sprintf("Posted \"%s\"", post.Title),
+			PostID:      &post.ID,
+		})
+	}
+
+	sort.SliceStable(timeline, func(i, j int) bool { return timeline[i].At.Before(timeline[j].At) })
+	return timeline
+}
+
+// GetVictimDossier gathers everything known about a network admin as a target.
+func GetVictimDossier(c *gin.Context) {
+	victimID := c.Param("id")
+	id, err := strconv.ParseUint(victimID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid victim ID"})
+		return
+	}
+
+	var victim models.User
+	if err := config.DB.Where("id = ? AND role = ?", id, models.RoleNetworkAdmin).First(&victim).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Network admin not found"})
+		return
+	}
+
+	var assignments []models.DemonVictim
+	if err := config.DB.Where("victim_id = ?", victim.ID).Preload("Demon").Order("created_at ASC").Find(&assignments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch assignments"})
+		return
+	}
+
+	var reports []models.Report
+	if err := config.DB.Where("victim_id = ?", victim.ID).Preload("Demon").Order("created_at ASC").Find(&reports).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reports"})
+		return
+	}
+	changes, err := statusHistories(config.DB, reports)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch report history"})
+		return
+	}
+
+	var posts []models.Post
+	if err := config.DB.Where("author_id = ? AND anonymous = ?", victim.ID, false).Order("created_at ASC").Find(&posts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch posts"})
+		return
+	}
+
+	var assets []models.Asset
+	if err := config.DB.Where("victim_id = ?", victim.ID).Preload("Services").Order("address ASC, hostname ASC").Find(&assets).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch assets"})
+		return
+	}
+
+	history, err := ownershipHistory(config.DB, victim.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch ownership history"})
+		return
+	}
+
+	targetedBy := []gin.H{}
+	for _, assignment := range assignments {
+		if assignment.Status == models.AssignmentStatusAbandoned {
+			continue
+		}
+		targetedBy = append(targetedBy, gin.H{
+			"assignment_id": assignment.ID,
+			"demon_id":      assignment.DemonID,
+			"username":      assignment.Demon.Username,
+			"status":        assignment.Status,
+			"since":         assignment.CreatedAt,
+			"due_at":        assignment.DueAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"victim": gin.H{
+			"id":         victim.ID,
+			"username":   victim.Username,
+			"email":      victim.Email,
+			"created_at": victim.CreatedAt,
+		},
+		"exposure":    exposure(assignments, reports, posts),
+		"targeted_by": targetedBy,
+		"assignments": assignments,
+		"reports":     reports,
+		"posts":       posts,
+		"assets":      assets,
+		"ownership":   history,
+		"timeline":    dossierTimeline(history, reports, changes, posts),
+	})
+}
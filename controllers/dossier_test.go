@@ -0,0 +1,33 @@
+package controllers
+
+import (
+	"testing"
+
+	"andrei-api/models"
+)
+
+func TestExposureIgnoresUnsubmittedReports(t *testing.T) {
+	reports := []models.Report{
+		{Status: models.ReportStatusDraft},
+		{Status: models.ReportStatusDiscarded},
+		{Status: models.ReportStatusPending},
+		{Status: models.ReportStatusReviewed},
+	}
+	assignments := []models.DemonVictim{{Status: models.AssignmentStatusTargeted}}
+
+	got := exposure(assignments, reports, nil)
+	if got.Factors["reports"] != 2*exposurePerReport {
+		t.Errorf("reports factor %d, want %d", got.Factors["reports"], 2*exposurePerReport)
+	}
+	if got.Factors["reviewed_reports"] != exposurePerReviewed {
+		t.Errorf("reviewed reports factor %d, want %d", got.Factors["reviewed_reports"], exposurePerReviewed)
+	}
+	if want := exposurePerDemon + 2*exposurePerReport + exposurePerReviewed; got.Score != want {
+		t.Errorf("score %d, want %d", got.Score, want)
+	}
+
+	drafts := exposure(nil, []models.Report{{Status: models.ReportStatusDraft}, {Status: models.ReportStatusDiscarded}}, nil)
+	if drafts.Score != 0 || drafts.Level != "low" {
+		t.Errorf("drafts alone scored %d (%s)", drafts.Score, drafts.Level)
+	}
+}
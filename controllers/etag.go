@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var errStaleVersion = errors.New("resource was modified concurrently")
+
+// versionETag is the entity tag of a versioned resource: its version number, quoted.
+func versionETag(version uint) string {
+	return strconv.Quote(strconv.FormatUint(uint64(version), 10))
+}
+
+func setETag(c *gin.Context, version uint) {
+	c.Header("ETag", versionETag(version))
+}
+
+// ifMatch checks the If-Match header against the current version; without the header any version matches.
+// If-Match uses the strong comparison, so weak tags (W/"3") never match.
+// It writes a 412 response with the current ETag and returns false when the client's version is stale.
+func ifMatch(c *gin.Context, version uint) bool {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		return true
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == versionETag(version) {
+			return true
+		}
+	}
+
+	setETag(c, version)
+	c.JSON(http.StatusPreconditionFailed, gin.H{"error": "The resource has been modified, reload it and retry", "version": version})
+	return false
+}
+
+func respondStaleVersion(c *gin.Context) {
+	c.JSON(http.StatusPreconditionFailed, gin.H{"error": "The resource was modified by someone else, reload it and retry"})
+}
+
+// versionedUpdate applies updates only if the row still has the expected version, and increments the version.
+func versionedUpdate(tx *gorm.DB, model interface{}, id, version uint, updates map[string]interface{}) error {
+	updates["version"] = gorm.Expr("version + 1")
+	result := tx.Model(model).Where("id = ? AND version = ?", id, version).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errStaleVersion
+	}
+	return nil
+}
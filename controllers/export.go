@@ -0,0 +1,256 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"andrei-api/config"
+	"andrei-api/models"
+	"andrei-api/pdf"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// exportBatchSize is how many reports are loaded at a time while streaming an export.
+const exportBatchSize = 100
+
+var csvHeader = []string{
+	"id", "demon_id", "demon", "victim_id", "victim", "title", "description", "status",
+	"template_id", "data", "cvss_vector", "cvss_score", "severity", "due_at", "overdue_at", "reviewed_at", "review_feedback", "created_at", "updated_at",
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func formatID(id *uint) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
+
+// csvSafe stops spreadsheets from evaluating cells as formulas.
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func csvRecord(report models.Report) []string {
+	data := ""
+	if report.Data != nil {
+		encoded, _ := json.Marshal(report.Data)
+		data = string(encoded)
+	}
+	score := ""
+	if report.CVSSScore != nil {
+		score = strconv.FormatFloat(*report.CVSSScore, 'f', 1, 64)
+	}
+
+	record := []string{
+		strconv.FormatUint(uint64(report.ID), 10),
+		strconv.FormatUint(uint64(report.DemonID), 10),
+		report.Demon.Username,
+		strconv.FormatUint(uint64(report.VictimID), 10),
+		report.Victim.Username,
+		report.Title,
+		report.Description,
+		string(report.Status),
+		formatID(report.TemplateID),
+		data,
+		report.CVSSVector,
+		score,
+		report.Severity,
+		formatTime(report.DueAt),
+		formatTime(report.OverdueAt),
+		formatTime(report.ReviewedAt),
+		report.ReviewFeedback,
+		report.CreatedAt.Format(time.RFC3339),
+		report.UpdatedAt.Format(time.RFC3339),
+	}
+	for i := range record {
+		record[i] = csvSafe(record[i])
+	}
+	return record
+}
+
+func writeReportPDF(document *pdf.Writer, report models.Report, history []models.ReportStatusChange) {
+	document.Title(fmt.Sprintf("Report #%d: %s", report.ID, report.Title))
+	document.Field("Demon:", fmt.Sprintf("%s (#%d)", report.Demon.Username, report.DemonID))
+	document.Field("Victim:", fmt.Sprintf("%s (#%d)", report.Victim.Username, report.VictimID))
+	document.Field("Status:", string(report.Status))
+	if report.CVSSScore != nil {
+		document.Field("Severity:", fmt.Sprintf("%s (%.1f)", report.Severity, *report.CVSSScore))
+		document.Field("CVSS vector:", report.CVSSVector)
+	}
+
+	document.Heading("Description")
+	document.Paragraph(report.Description)
+
+	if len(report.Data) > 0 {
+		document.Heading("Structured data")
+		keys := make([]string, 0, len(report.Data))
+		for key := range report.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			encoded, _ := json.Marshal(report.Data[key])
+			document.Field(key+":", strings.Trim(string(encoded), `"`))
+		}
+	}
+
+	document.Heading("Timeline")
+	document.Field("Created:", report.CreatedAt.Format(time.RFC1123))
+	if report.DueAt != nil {
+		document.Field("Due:", report.DueAt.Format(time.RFC1123))
+	}
+	if report.OverdueAt != nil {
+		document.Field("Flagged overdue:", report.OverdueAt.Format(time.RFC1123))
+	}
+	if report.ReviewedAt != nil {
+		document.Field("Reviewed:", report.ReviewedAt.Format(time.RFC1123))
+		if report.ReviewFeedback != "" {
+			document.Field("Feedback:", report.ReviewFeedback)
+		}
+	}
+	document.Field("Last updated:", report.UpdatedAt.Format(time.RFC1123))
+
+	document.Heading("Status history")
+	if len(history) == 0 {
+		document.Paragraph("No status changes recorded.")
+	}
+	for _, change := range history {
+		from := string(change.FromStatus)
+		if from == "" {
+			from = "new"
+		}
+		line := fmt.Sprintf("%s  %s -> %s by %s", change.CreatedAt.Format(time.RFC1123), from, change.ToStatus, change.ChangedBy.Username)
+		if change.Note != "" {
+			line += ": " + change.Note
+		}
+		document.Paragraph(line)
+	}
+}
+
+func statusHistories(tx *gorm.DB, reports []models.Report) (map[uint][]models.ReportStatusChange, error) {
+	ids := make([]uint, len(reports))
+	for i, report := range reports {
+		ids[i] = report.ID
+	}
+
+	var changes []models.ReportStatusChange
+	if err := tx.Where("report_id IN ?", ids).Preload("ChangedBy").Order("created_at ASC, id ASC").Find(&changes).Error; err != nil {
+		return nil, err
+	}
+
+	histories := make(map[uint][]models.ReportStatusChange, len(reports))
+	for _, change := range changes {
+		histories[change.ReportID] = append(histories[change.ReportID], change)
+	}
+	return histories, nil
+}
+
+// streamReports writes the reports selected by query as CSV or PDF, loading them in batches
+// and flushing the response after each batch. Errors after the headers are sent can only be logged.
+func streamReports(c *gin.Context, query *gorm.DB, format, filename string) {
+	var writeBatch func(reports []models.Report, histories map[uint][]models.ReportStatusChange)
+	var finish func() error
+
+	switch format {
+	case "csv":
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		writer := csv.NewWriter(c.Writer)
+		writer.Write(csvHeader)
+		writeBatch = func(reports []models.Report, _ map[uint][]models.ReportStatusChange) {
+			for _, report := range reports {
+				writer.Write(csvRecord(report))
+			}
+			writer.Flush()
+		}
+		finish = func() error {
+			writer.Flush()
+			return writer.Error()
+		}
+	case "pdf":
+		c.Header("Content-Type", "application/pdf")
+		document := pdf.NewWriter(c.Writer)
+		writeBatch = func(reports []models.Report, histories map[uint][]models.ReportStatusChange) {
+			for _, report := range reports {
+				document.PageBreak()
+				writeReportPDF(document, report, histories[report.ID])
+			}
+		}
+		finish = document.Close
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format. Use csv or pdf"})
+		return
+	}
+
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename + "." + format}))
+	c.Status(http.StatusOK)
+
+	var batch []models.Report
+	err := query.Preload("Demon").Preload("Victim").FindInBatches(&batch, exportBatchSize, func(tx *gorm.DB, _ int) error {
+		var histories map[uint][]models.ReportStatusChange
+		if format == "pdf" {
+			var err error
+			if histories, err = statusHistories(config.DB, batch); err != nil {
+				return err
+			}
+		}
+		writeBatch(batch, histories)
+		c.Writer.Flush()
+		return nil
+	}).Error
+	if err != nil {
+		log.Printf("Failed to export reports: %v", err)
+	}
+
+	if err := finish(); err != nil {
+		log.Printf("Failed to finish report export: %v", err)
+	}
+}
+
+// ExportReports exports the reports matching the report listing filters (?format=csv or pdf).
+func ExportReports(c *gin.Context) {
+	query, ok := filterReports(c, config.DB.Model(&models.Report{}))
+	if !ok {
+		return
+	}
+
+	filename := "reports-" + time.Now().Format("20060102-150405")
+	streamReports(c, query, c.DefaultQuery("format", "csv"), filename)
+}
+
+func ExportReport(c *gin.Context) {
+	reportID := c.Param("id")
+	id, err := strconv.ParseUint(reportID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report ID"})
+		return
+	}
+
+	var count int64
+	if err := config.DB.Model(&models.Report{}).Where("id = ?", id).Count(&count).Error; err != nil || count == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Report not found"})
+		return
+	}
+
+	query := config.DB.Model(&models.Report{}).Where("reports.id = ?", id)
+	streamReports(c, query, c.DefaultQuery("format", "pdf"), fmt.Sprintf("report-%d", id))
+}
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// pagination reads ?page= and ?limit= and returns the page, limit and offset to apply.
+func pagination(c *gin.Context) (int, int, int) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultPageSize)))
+	if err != nil || limit < 1 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	return page, limit, (page - 1) * limit
+}
+
+// dateParam parses an optional RFC 3339 or YYYY-MM-DD query parameter.
+// It writes a 400 response and returns false when the value is invalid.
+func dateParam(c *gin.Context, name string) (*time.Time, bool) {
+	parsed, _, ok := parseDateParam(c, name)
+	return parsed, ok
+}
+
+// endDateParam parses an optional exclusive upper bound, to be compared with <. A plain date covers
+// that whole day, so to=2026-10-18 ends at midnight on October 19.
+func endDateParam(c *gin.Context, name string) (*time.Time, bool) {
+	parsed, dateOnly, ok := parseDateParam(c, name)
+	if parsed != nil && dateOnly {
+		next := parsed.AddDate(0, 0, 1)
+		parsed = &next
+	}
+	return parsed, ok
+}
+
+func parseDateParam(c *gin.Context, name string) (*time.Time, bool, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, false, true
+	}
+
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return &parsed, false, true
+	}
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + name + " date, use RFC 3339 or YYYY-MM-DD"})
+		return nil, false, false
+	}
+	return &parsed, true, true
+}
+
+// timeRange reads ?from= and the exclusive ?to=, defaulting to the last defaultDays days.
+func timeRange(c *gin.Context, defaultDays int) (time.Time, time.Time, bool) {
+	to := time.Now()
+	from := to.AddDate(0, 0, -defaultDays)
+
+	fromParam, ok := dateParam(c, "from")
+	if !ok {
+		return from, to, false
+	}
+	toParam, ok := endDateParam(c, "to")
+	if !ok {
+		return from, to, false
+	}
+
+	if fromParam != nil {
+		from = *fromParam
+	}
+	if toParam != nil {
+		to = *toParam
+	}
+	return from, to, true
+}
@@ -0,0 +1,154 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"andrei-api/config"
+	"andrei-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// rankingQuery aggregates every demon's stats in one pass and ranks them by the scoring formula.
+// Demons with the same score share a rank. The {{window}} placeholders hold the optional season filter.
+const rankingQuery = `
+WITH weights AS (
+	SELECT CAST(@points_weight AS double precision) AS points,
+		CAST(@reports_weight AS double precision) AS reports,
+		CAST(@victims_weight AS double precision) AS victims,
+		CAST(@punishments_weight AS double precision) AS punishments
+), reward_totals AS (
+	SELECT demon_id,
+		COUNT(*) FILTER (WHERE type = @reward_type) AS rewards_count,
+		COUNT(*) FILTER (WHERE type = @punishment_type) AS punishments_count,
+		COALESCE(SUM(points), 0) AS total_points
+	FROM rewards
+	WHERE deleted_at IS NULL {{window}}
+	GROUP BY demon_id
+), report_totals AS (
+	SELECT reports.demon_id,
+		COUNT(*) AS reports_count,
+		COUNT(DISTINCT victims.id) AS victims_count
+	FROM reports
+	LEFT JOIN users victims ON victims.id = reports.victim_id AND victims.role = @network_admin AND victims.deleted_at IS NULL
+	WHERE reports.deleted_at IS NULL AND reports.status NOT IN @uncounted {{reports_window}}
+	GROUP BY reports.demon_id
+), totals AS (
+	SELECT users.id AS demon_id,
+		users.username,
+		COALESCE(reward_totals.total_points, 0) AS total_points,
+		COALESCE(reward_totals.rewards_count, 0) AS rewards_count,
+		COALESCE(reward_totals.punishments_count, 0) AS punishments_count,
+		COALESCE(report_totals.reports_count, 0) AS reports_count,
+		COALESCE(report_totals.victims_count, 0) AS victims_count
+	FROM users
+	LEFT JOIN reward_totals ON reward_totals.demon_id = users.id
+	LEFT JOIN report_totals ON report_totals.demon_id = users.id
+	WHERE users.role = @demon AND users.deleted_at IS NULL
+), scored AS (
+	SELECT totals.*,
+		weights.points * total_points + weights.reports * reports_count
+			+ weights.victims * victims_count - weights.punishments * punishments_count AS score
+	FROM totals CROSS JOIN weights
+)
+SELECT scored.*, RANK() OVER (ORDER BY score DESC) AS rank
+FROM scored
+ORDER BY rank ASC, demon_id ASC`
+
+func loadScoringFormula() (models.ScoringFormula, error) {
+	formula := models.DefaultScoringFormula
+	err := config.DB.First(&formula, models.DefaultScoringFormula.ID).Error
+	if err == gorm.ErrRecordNotFound {
+		return models.DefaultScoringFormula, nil
+	}
+	return formula, err
+}
+
+// rankDemons returns one page of the ranking. A nil season ranks on all-time totals;
+// limit <= 0 returns every demon.
+func rankDemons(db *gorm.DB, season *models.Season, formula models.ScoringFormula, limit, offset int) ([]models.RankingEntry, error) {
+	args := map[string]interface{}{
+		"reward_type":        models.RewardTypeReward,
+		"punishment_type":    models.RewardTypePunishment,
+		"network_admin":      models.RoleNetworkAdmin,
+		"uncounted":          uncountedReportStatuses,
+		"demon":              models.RoleDemon,
+		"points_weight":      formula.PointsWeight,
+		"reports_weight":     formula.ReportsWeight,
+		"victims_weight":     formula.VictimsWeight,
+		"punishments_weight": formula.PunishmentsWeight,
+	}
+
+	window, reportsWindow := "", ""
+	if season != nil {
+		window = "AND created_at >= @starts_at"
+		reportsWindow = "AND reports.created_at >= @starts_at"
+		args["starts_at"] = season.StartsAt
+		if season.EndsAt != nil {
+			window += " AND created_at < @ends_at"
+			reportsWindow += " AND reports.created_at < @ends_at"
+			args["ends_at"] = *season.EndsAt
+		}
+	}
+
+	query := strings.NewReplacer("{{window}}", window, "{{reports_window}}", reportsWindow).Replace(rankingQuery)
+	if limit > 0 {
+		query += " LIMIT @limit OFFSET @offset"
+		args["limit"] = limit
+		args["offset"] = offset
+	}
+
+	entries := []models.RankingEntry{}
+	err := db.Raw(query, args).Scan(&entries).Error
+	return entries, err
+}
+
+func GetScoringFormula(c *gin.Context) {
+	formula, err := loadScoringFormula()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch scoring formula"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"formula": formula})
+}
+
+func UpdateScoringFormula(c *gin.Context) {
+	var input models.ScoringFormulaUpdate
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	formula, err := loadScoringFormula()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch scoring formula"})
+		return
+	}
+
+	if input.PointsWeight != nil {
+		formula.PointsWeight = *input.PointsWeight
+	}
+	if input.ReportsWeight != nil {
+		formula.ReportsWeight = *input.ReportsWeight
+	}
+	if input.VictimsWeight != nil {
+		formula.VictimsWeight = *input.VictimsWeight
+	}
+	if input.PunishmentsWeight != nil {
+		formula.PunishmentsWeight = *input.PunishmentsWeight
+	}
+
+	user := c.MustGet("user").(models.User)
+	formula.UpdatedByID = &user.ID
+
+	if err := config.DB.Save(&formula).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update scoring formula"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"formula": formula})
+}
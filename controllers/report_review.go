@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"andrei-api/config"
+	"andrei-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// filterReports applies the demon_id, victim_id, status (comma separated), from and to query filters,
+// then the severity, technique and structured data filters.
+// It writes a 400 response and returns false when a filter is invalid.
+func filterReports(c *gin.Context, query *gorm.DB) (*gorm.DB, bool) {
+	for _, param := range []string{"demon_id", "victim_id"} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param})
+			return nil, false
+		}
+		query = query.Where("reports."+param+" = ?", id)
+	}
+
+	if status := c.Query("status"); status != "" {
+		query = query.Where("reports.status IN ?", strings.Split(status, ","))
+	}
+
+	from, ok := dateParam(c, "from")
+	if !ok {
+		return nil, false
+	}
+	if from != nil {
+		query = query.Where("reports.created_at >= ?", *from)
+	}
+	to, ok := endDateParam(c, "to")
+	if !ok {
+		return nil, false
+	}
+	if to != nil {
+		query = query.Where("reports.created_at < ?", *to)
+	}
+
+	query, ok = filterReportSeverity(c, query)
+	if !ok {
+		return nil, false
+	}
+	query, ok = filterReportTechnique(c, query)
+	if !ok {
+		return nil, false
+	}
+
+	return filterReportData(c, query)
+}
+
+func listReports(c *gin.Context, query *gorm.DB, order string) {
+	query, ok := filterReports(c, query)
+	if !ok {
+		return
+	}
+	order, ok = reportOrder(c, order)
+	if !ok {
+		return
+	}
+	// Share the filters between the count and the page query
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reports"})
+		return
+	}
+
+	page, limit, offset := pagination(c)
+	var reports []models.Report
+	if err := query.Preload("Demon").Preload("Victim").Preload("Techniques").Order(order).Limit(limit).Offset(offset).Find(&reports).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reports"})
+		return
+	}
+	withReportTechniqueNames(reports)
+
+	withUnreadComments(c.MustGet("user").(models.User).ID, reports)
+
+	c.JSON(http.StatusOK, gin.H{"reports": reports, "page": page, "limit": limit, "total": total})
+}
+
+func GetReports(c *gin.Context) {
+	listReports(c, config.DB.Model(&models.Report{}), "reports.created_at DESC")
+}
+
+// GetReviewQueue lists the reports awaiting andrei's review, oldest first.
+func GetReviewQueue(c *gin.Context) {
+	query := config.DB.Model(&models.Report{}).
+		Where("reports.status IN ?", []models.ReportStatus{models.ReportStatusCompleted, models.ReportStatusFailed})
+	listReports(c, query, "reports.updated_at ASC")
+}
+
+func GetReport(c *gin.Context) {
+	reportID := c.Param("id")
+	id, err := strconv.ParseUint(reportID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report ID"})
+		return
+	}
+
+	var report models.Report
+	if err := config.DB.Preload("Demon").Preload("Victim").Preload("Assets.Services").Preload("Techniques").First(&report, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Report not found"})
+		return
+	}
+	withTechniqueNames(report.Techniques)
+
+	setETag(c, report.Version)
+	c.JSON(http.StatusOK, gin.H{"report": report})
+}
+
+func ReviewReport(c *gin.Context) {
+	reportID := c.Param("id")
+	id, err := strconv.ParseUint(reportID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report ID"})
+		return
+	}
+
+	var input models.ReportReview
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if input.Decision == "reject" && input.Points > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Points can only be awarded when approving a report"})
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+
+	var report models.Report
+	if err := config.DB.First(&report, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Report not found"})
+		return
+	}
+	if !ifMatch(c, report.Version) {
+		return
+	}
+
+	status := models.ReportStatusReviewed
+	if input.Decision == "reject" {
+		status = models.ReportStatusRejected
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := changeReportStatus(tx, &report, status, user, input.Feedback); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		report.ReviewFeedback = input.Feedback
+		report.ReviewedByID = &user.ID
+		report.ReviewedAt = &now
+
+		if input.Points > 0 {
+			reward := models.Reward{
+				DemonID:     report.DemonID,
+				Type:        models.RewardTypeReward,
+				Title:       "Report approved: " + report.Title,
+				Description: input.Feedback,
+				Points:      input.Points,
+			}
+			if reward.Description == "" {
+				reward.Description = "Awarded on report review"
+			}
+			if err := tx.Create(&reward).Error; err != nil {
+				return err
+			}
+			report.ReviewRewardID = &reward.ID
+		}
+
+		return tx.Model(&report).Select("ReviewFeedback", "ReviewedByID", "ReviewedAt", "ReviewRewardID").Updates(&report).Error
+	})
+	if err != nil {
+		respondStatusError(c, report, user, err)
+		return
+	}
+
+	if input.Points > 0 {
+		refreshAchievements(report.DemonID)
+	}
+
+	setETag(c, report.Version)
+	c.JSON(http.StatusOK, gin.H{"report": report})
+}
@@ -0,0 +1,219 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"andrei-api/config"
+	"andrei-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var (
+	errUnknownReportStatus = errors.New("unknown report status")
+	errTransitionForbidden = errors.New("status transition not allowed")
+	errReportStatusChanged = errors.New("report status changed concurrently")
+)
+
+// reportTransitions lists, for each status, the statuses it can move to and the role allowed to move it.
+// Demons drive their own work; andrei reviews the outcome.
+var reportTransitions = map[models.ReportStatus]map[models.ReportStatus]models.UserRole{
+	models.ReportStatusDraft: {
+		models.ReportStatusPending:   models.RoleDemon,
+		models.ReportStatusDiscarded: models.RoleDemon,
+	},
+	models.ReportStatusPending: {
+		models.ReportStatusInProgress: models.RoleDemon,
+		models.ReportStatusFailed:     models.RoleDemon,
+	},
+	models.ReportStatusInProgress: {
+		models.ReportStatusCompleted: models.RoleDemon,
+		models.ReportStatusFailed:    models.RoleDemon,
+	},
+	models.ReportStatusCompleted: {
+		models.ReportStatusReviewed: models.RoleAndrei,
+		models.ReportStatusRejected: models.RoleAndrei,
+	},
+	models.ReportStatusFailed: {
+		models.ReportStatusReviewed: models.RoleAndrei,
+		models.ReportStatusRejected: models.RoleAndrei,
+	},
+	models.ReportStatusRejected: {
+		models.ReportStatusInProgress: models.RoleDemon,
+	},
+}
+
+// uncountedReportStatuses are left out of rankings, statistics and badges: drafts have not been
+// submitted yet and discarded drafts never will be.
+var uncountedReportStatuses = []models.ReportStatus{models.ReportStatusDraft, models.ReportStatusDiscarded}
+
+// countedReport reports whether a report with the given status counts as submitted work.
+func countedReport(status models.ReportStatus) bool {
+	for _, uncounted := range uncountedReportStatuses {
+		if status == uncounted {
+			return false
+		}
+	}
+	return true
+}
+
+func validReportStatus(status models.ReportStatus) bool {
+	switch status {
+	case models.ReportStatusDraft, models.ReportStatusPending, models.ReportStatusInProgress, models.ReportStatusCompleted,
+		models.ReportStatusFailed, models.ReportStatusReviewed, models.ReportStatusRejected, models.ReportStatusDiscarded:
+		return true
+	}
+	return false
+}
+
+// allowedTransitions returns the statuses the given role can move a report to from its current status.
+func allowedTransitions(from models.ReportStatus, role models.UserRole) []models.ReportStatus {
+	allowed := []models.ReportStatus{}
+	for to, allowedRole := range reportTransitions[from] {
+		if allowedRole == role {
+			allowed = append(allowed, to)
+		}
+	}
+	return allowed
+}
+
+// recordReportStatus appends an entry to the report's status history.
+func recordReportStatus(tx *gorm.DB, reportID uint, from, to models.ReportStatus, changedByID uint, note string) error {
+	change := models.ReportStatusChange{
+		ReportID:    reportID,
+		FromStatus:  from,
+		ToStatus:    to,
+		ChangedByID: changedByID,
+		Note:        note,
+	}
+	return tx.Create(&change).Error
+}
+
+// changeReportStatus validates and applies a transition, recording it in the status history.
+// The update only succeeds if the report has not changed since it was read, and bumps its version.
+func changeReportStatus(tx *gorm.DB, report *models.Report, to models.ReportStatus, user models.User, note string) error {
+	if !validReportStatus(to) {
+		return errUnknownReportStatus
+	}
+	if reportTransitions[report.Status][to] != user.Role {
+		return errTransitionForbidden
+	}
+
+	from := report.Status
+	result := tx.Model(&models.Report{}).Where("id = ? AND status = ? AND version = ?", report.ID, from, report.Version).
+		Updates(map[string]interface{}{"status": to, "version": gorm.Expr("version + 1")})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errReportStatusChanged
+	}
+
+	report.Status = to
+	report.Version++
+	return recordReportStatus(tx, report.ID, from, to, user.ID, note)
+}
+
+// respondStatusError maps changeReportStatus errors to HTTP responses.
+func respondStatusError(c *gin.Context, report models.Report, user models.User, err error) {
+	switch {
+	case errors.Is(err, errUnknownReportStatus):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+	case errors.Is(err, errTransitionForbidden):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "Status transition not allowed",
+			"status":  report.Status,
+			"allowed": allowedTransitions(report.Status, user.Role),
+		})
+	case errors.Is(err, errReportStatusChanged):
+		respondStaleVersion(c)
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update report"})
+	}
+}
+
+func reportHistory(c *gin.Context, report models.Report) {
+	var history []models.ReportStatusChange
+	if err := config.DB.Where("report_id = ?", report.ID).Preload("ChangedBy").Order("created_at ASC, id ASC").Find(&history).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch report history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"report_id": report.ID, "status": report.Status, "history": history})
+}
+
+func GetMyReportHistory(c *gin.Context) {
+	reportID := c.Param("id")
+	id, err := strconv.ParseUint(reportID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report ID"})
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+
+	var report models.Report
+	if err := config.DB.Where("id = ? AND demon_id = ?", id, user.ID).First(&report).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Report not found"})
+		return
+	}
+
+	reportHistory(c, report)
+}
+
+func GetReportHistory(c *gin.Context) {
+	reportID := c.Param("id")
+	id, err := strconv.ParseUint(reportID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report ID"})
+		return
+	}
+
+	var report models.Report
+	if err := config.DB.First(&report, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Report not found"})
+		return
+	}
+
+	reportHistory(c, report)
+}
+
+func AndreiUpdateReportStatus(c *gin.Context) {
+	reportID := c.Param("id")
+	id, err := strconv.ParseUint(reportID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report ID"})
+		return
+	}
+
+	var input models.ReportStatusUpdate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+
+	var report models.Report
+	if err := config.DB.First(&report, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Report not found"})
+		return
+	}
+	if !ifMatch(c, report.Version) {
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		return changeReportStatus(tx, &report, input.Status, user, input.Note)
+	})
+	if err != nil {
+		respondStatusError(c, report, user, err)
+		return
+	}
+
+	setETag(c, report.Version)
+	c.JSON(http.StatusOK, gin.H{"report": report})
+}
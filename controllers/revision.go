@@ -0,0 +1,277 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"andrei-api/config"
+	"andrei-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// recordRevision stores the report's current content as its next revision.
+func recordRevision(tx *gorm.DB, report models.Report, editedByID uint) (models.ReportRevision, error) {
+	var last int
+	if err := tx.Model(&models.ReportRevision{}).Where("report_id = ?", report.ID).
+		Select("COALESCE(MAX(number), 0)").Scan(&last).Error; err != nil {
+		return models.ReportRevision{}, err
+	}
+
+	revision := models.ReportRevision{
+		ReportID:    report.ID,
+		Number:      last + 1,
+		Title:       report.Title,
+		Description: report.Description,
+		Data:        report.Data,
+		CVSSVector:  report.CVSSVector,
+		EditedByID:  editedByID,
+	}
+	return revision, tx.Create(&revision).Error
+}
+
+// diffRevisions lists the fields that differ between two revisions.
+func diffRevisions(from, to models.ReportRevision) []models.FieldChange {
+	changes := []models.FieldChange{}
+	if from.Title != to.Title {
+		changes = append(changes, models.FieldChange{Field: "title", From: from.Title, To: to.Title})
+	}
+	if from.Description != to.Description {
+		changes = append(changes, models.FieldChange{Field: "description", From: from.Description, To: to.Description})
+	}
+	if from.CVSSVector != to.CVSSVector {
+		changes = append(changes, models.FieldChange{Field: "cvss_vector", From: from.CVSSVector, To: to.CVSSVector})
+	}
+
+	keys := make(map[string]bool)
+	for key := range from.Data {
+		keys[key] = true
+	}
+	for key := range to.Data {
+		keys[key] = true
+	}
+	names := make([]string, 0, len(keys))
+	for key := range keys {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+
+	for _, key := range names {
+		before, after := from.Data[key], to.Data[key]
+		if !reflect.DeepEqual(before, after) {
+			changes = append(changes, models.FieldChange{Field: "data." + key, From: before, To: after})
+		}
+	}
+	return changes
+}
+
+var errReportReviewed = errors.New("report already reviewed")
+
+// EditReport lets the owning demon change a report's content. Every edit is stored as a revision.
+func EditReport(c *gin.Context) {
+	reportID := c.Param("id")
+	id, err := strconv.ParseUint(reportID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report ID"})
+		return
+	}
+
+	var input models.ReportEdit
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.Title == nil && input.Description == nil && input.Data == nil && input.CVSSVector == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nothing to change"})
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+
+	var report models.Report
+	if err := config.DB.Where("id = ? AND demon_id = ?", id, user.ID).First(&report).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Report not found"})
+		return
+	}
+
+	if input.Data != nil {
+		if report.TemplateID == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Structured data requires a template_id"})
+			return
+		}
+		var template models.ReportTemplate
+		if err := config.DB.Unscoped().First(&template, *report.TemplateID).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load report template"})
+			return
+		}
+		if problems := validateReportData(template.Schema, input.Data); len(problems) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Report data does not match the template", "details": problems})
+			return
+		}
+	}
+
+	var scored models.Report
+	if input.CVSSVector != nil {
+		if err := applyCVSS(&scored, *input.CVSSVector); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid CVSS vector: " + err.Error()})
+			return
+		}
+	}
+
+	if !ifMatch(c, report.Version) {
+		return
+	}
+
+	var revision models.ReportRevision
+	version := report.Version
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		// Lock the report so concurrent edits get consecutive revision numbers
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&report, report.ID).Error; err != nil {
+			return err
+		}
+		if report.Version != version {
+			return errStaleVersion
+		}
+		if report.Status == models.ReportStatusReviewed {
+			return errReportReviewed
+		}
+
+		// Reports created before revisions were kept get their original content as revision 1
+		var count int64
+		if err := tx.Model(&models.ReportRevision{}).Where("report_id = ?", report.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			if _, err := recordRevision(tx, report, report.DemonID); err != nil {
+				return err
+			}
+		}
+
+		if input.Title != nil {
+			report.Title = *input.Title
+		}
+		if input.Description != nil {
+			report.Description = *input.Description
+		}
+		if input.Data != nil {
+			report.Data = input.Data
+		}
+		if input.CVSSVector != nil {
+			report.CVSSVector, report.CVSSScore, report.Severity = scored.CVSSVector, scored.CVSSScore, scored.Severity
+		}
+		updates := map[string]interface{}{
+			"title":       report.Title,
+			"description": report.Description,
+			"data":        report.Data,
+			"cvss_vector": report.CVSSVector,
+			"cvss_score":  report.CVSSScore,
+			"severity":    report.Severity,
+		}
+		if err := versionedUpdate(tx, &models.Report{}, report.ID, report.Version, updates); err != nil {
+			return err
+		}
+		report.Version++
+
+		var err error
+		revision, err = recordRevision(tx, report, user.ID)
+		return err
+	})
+	if errors.Is(err, errReportReviewed) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Reviewed reports can no longer be edited"})
+		return
+	}
+	if errors.Is(err, errStaleVersion) {
+		respondStaleVersion(c)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update report"})
+		return
+	}
+
+	setETag(c, report.Version)
+	c.JSON(http.StatusOK, gin.H{"report": report, "revision": revision.Number})
+}
+
+func GetReportRevisions(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	report, ok := reportForUser(c, user)
+	if !ok {
+		return
+	}
+
+	var revisions []models.ReportRevision
+	if err := config.DB.Where("report_id = ?", report.ID).Preload("EditedBy").Order("number ASC").Find(&revisions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch revisions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"report_id": report.ID, "revisions": revisions})
+}
+
+// GetReportRevisionDiff compares revisions ?from= and ?to= (default: the previous and the latest revision).
+func GetReportRevisionDiff(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	report, ok := reportForUser(c, user)
+	if !ok {
+		return
+	}
+
+	var latest int
+	if err := config.DB.Model(&models.ReportRevision{}).Where("report_id = ?", report.ID).
+		Select("COALESCE(MAX(number), 0)").Scan(&latest).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch revisions"})
+		return
+	}
+	if latest == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "This report has no revisions"})
+		return
+	}
+
+	numbers := map[string]int{"from": latest - 1, "to": latest}
+	if numbers["from"] < 1 {
+		numbers["from"] = 1
+	}
+	for _, param := range []string{"from", "to"} {
+		if value := c.Query(param); value != "" {
+			number, err := strconv.Atoi(value)
+			if err != nil || number < 1 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param + " revision"})
+				return
+			}
+			numbers[param] = number
+		}
+	}
+
+	var revisions []models.ReportRevision
+	if err := config.DB.Where("report_id = ? AND number IN ?", report.ID, []int{numbers["from"], numbers["to"]}).
+		Find(&revisions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch revisions"})
+		return
+	}
+
+	byNumber := make(map[int]models.ReportRevision, len(revisions))
+	for _, revision := range revisions {
+		byNumber[revision.Number] = revision
+	}
+	from, okFrom := byNumber[numbers["from"]]
+	to, okTo := byNumber[numbers["to"]]
+	if !okFrom || !okTo {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Revision not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"report_id": report.ID,
+		"from":      from.Number,
+		"to":        to.Number,
+		"changes":   diffRevisions(from, to),
+	})
+}
@@ -0,0 +1,520 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"andrei-api/config"
+	"andrei-api/models"
+	"andrei-api/nmap"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var errImportCommitted = errors.New("scan import already committed")
+
+// importStep is the plan for one scanned host, with the existing asset it matched (nil when a new one is created).
+type importStep struct {
+	plan  models.ImportHostPlan
+	host  models.ScanHost
+	asset *models.Asset
+}
+
+// scanHosts converts the hosts of an Nmap run into validated scan hosts. Hosts whose address cannot
+// be used are skipped and described in the returned messages. A host listed more than once, e.g. when
+// it was scanned both by address and by hostname, is merged into its first entry.
+func scanHosts(run *nmap.Run) (models.ScanHosts, []string) {
+	hosts := models.ScanHosts{}
+	var skipped []string
+	for _, host := range run.Hosts {
+		input := models.AssetInput{Address: host.Address, OS: host.OS}
+		for _, hostname := range host.Hostnames {
+			hostname = strings.TrimSuffix(strings.ToLower(hostname), ".")
+			if len(hostname) <= 253 && hostnamePattern.MatchString(hostname) {
+				input.Hostname = hostname
+				break
+			}
+		}
+		seen := make(map[string]bool)
+		for _, port := range host.Ports {
+			// IP protocol scans (-sO) report protocols, not ports
+			if port.Protocol != "tcp" && port.Protocol != "udp" && port.Protocol != "sctp" {
+				continue
+			}
+			key := fmt.Sprintf("%d/%s", port.Port, port.Protocol)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			input.Services = append(input.Services, models.AssetServiceInput{
+				Port:     port.Port,
+				Protocol: port.Protocol,
+				Service:  port.Service,
+				Version:  port.Describe(),
+			})
+		}
+
+		asset, problems := buildAsset(input)
+		if len(problems) > 0 {
+			label := host.Address
+			if label == "" {
+				label = strings.Join(host.Hostnames, ", ")
+			}
+			skipped = append(skipped, label+": "+strings.Join(problems, "; "))
+			continue
+		}
+
+		scanned := models.ScanHost{Address: asset.Address, Hostname: asset.Hostname, OS: asset.OS, Services: []models.ScanService{}}
+		for _, service := range asset.Services {
+			scanned.Services = append(scanned.Services, models.ScanService{
+				Port:        service.Port,
+				Protocol:    service.Protocol,
+				Service:     service.Service,
+				Description: service.Version,
+			})
+		}
+		if i := sameScanHost(hosts, scanned); i >= 0 {
+			mergeScanHost(&hosts[i], scanned)
+			continue
+		}
+		hosts = append(hosts, scanned)
+	}
+	return hosts, skipped
+}
+
+// sameScanHost finds the host already listed with the same address, or with the same hostname when
+// one of them has no address. It returns -1 when there is none.
+func sameScanHost(hosts models.ScanHosts, host models.ScanHost) int {
+	for i, listed := range hosts {
+		if host.Address != "" && listed.Address == host.Address {
+			return i
+		}
+		if host.Hostname != "" && listed.Hostname == host.Hostname && (host.Address == "" || listed.Address == "") {
+			return i
+		}
+	}
+	return -1
+}
+
+// mergeScanHost fills in what the first entry of a host is missing from a later one and adds the
+// services it did not list.
+func mergeScanHost(host *models.ScanHost, other models.ScanHost) {
+	if host.Address == "" {
+		host.Address = other.Address
+	}
+	if host.Hostname == "" {
+		host.Hostname = other.Hostname
+	}
+	if host.OS == "" {
+		host.OS = other.OS
+	}
+	for _, service := range other.Services {
+		listed := false
+		for i := range host.Services {
+			if host.Services[i].Port != service.Port || host.Services[i].Protocol != service.Protocol {
+				continue
+			}
+			listed = true
+			if host.Services[i].Service == "" {
+				host.Services[i].Service = service.Service
+			}
+			if host.Services[i].Description == "" {
+				host.Services[i].Description = service.Description
+			}
+		}
+		if !listed {
+			host.Services = append(host.Services, service)
+		}
+	}
+}
+
+func hostLabel(host models.ScanHost) string {
+	switch {
+	case host.Hostname != "" && host.Address != "":
+		return fmt.Sprintf("%s (%s)", host.Hostname, host.Address)
+	case host.Hostname != "":
+		return host.Hostname
+	}
+	return host.Address
+}
+
+func draftTitle(host models.ScanHost, service models.ScanService) string {
+	name := service.Service
+	if name == "" {
+		name = "unknown service"
+	}
+	return fmt.Sprintf("%d/%s %s on %s", service.Port, service.Protocol, name, hostLabel(host))
+}
+
+// planScanImport matches each scanned host with the victim's assets, by address or else by hostname,
+// and works out what to change: missing hostnames and addresses are filled in, the OS guess is refreshed,
+// and services are added or updated. A draft report is planned for every new service.
+// An asset is matched by one host at most, so two hosts never add the same service to it.
+func planScanImport(db *gorm.DB, victimID uint, hosts models.ScanHosts) ([]importStep, error) {
+	var assets []models.Asset
+	if err := db.Where("victim_id = ?", victimID).Preload("Services").Find(&assets).Error; err != nil {
+		return nil, err
+	}
+
+	matched := make(map[uint]bool)
+	steps := make([]importStep, 0, len(hosts))
+	for _, host := range hosts {
+		step := importStep{host: host, plan: models.ImportHostPlan{
+			Address:     host.Address,
+			Hostname:    host.Hostname,
+			Action:      "create",
+			NewServices: []models.AssetService{},
+			Updated:     []models.AssetService{},
+			Drafts:      []string{},
+		}}
+		for i := range assets {
+			if matched[assets[i].ID] {
+				continue
+			}
+			if host.Address != "" && assets[i].Address == host.Address {
+				step.asset = &assets[i]
+				break
+			}
+		}
+		// Fall back to the hostname when one side has no address
+		for i := range assets {
+			if step.asset == nil && !matched[assets[i].ID] && host.Hostname != "" && assets[i].Hostname == host.Hostname &&
+				(host.Address == "" || assets[i].Address == "") {
+				step.asset = &assets[i]
+			}
+		}
+
+		existing := make(map[string]models.AssetService)
+		if step.asset != nil {
+			matched[step.asset.ID] = true
+			step.plan.AssetID = &step.asset.ID
+			step.plan.Action = "unchanged"
+			for _, service := range step.asset.Services {
+				existing[fmt.Sprintf("%d/%s", service.Port, service.Protocol)] = service
+			}
+			if (step.asset.Hostname == "" && host.Hostname != "") || (step.asset.Address == "" && host.Address != "") ||
+				(host.OS != "" && host.OS != step.asset.OS) {
+				step.plan.Action = "update"
+			}
+		}
+
+		for _, scanned := range host.Services {
+			service, found := existing[fmt.Sprintf("%d/%s", scanned.Port, scanned.Protocol)]
+			if !found {
+				step.plan.NewServices = append(step.plan.NewServices, models.AssetService{
+					Port:     scanned.Port,
+					Protocol: scanned.Protocol,
+					Service:  scanned.Service,
+					Version:  scanned.Description,
+				})
+				step.plan.Drafts = append(step.plan.Drafts, draftTitle(host, scanned))
+				continue
+			}
+			if (scanned.Service != "" && scanned.Service != service.Service) || (scanned.Description != "" && scanned.Description != service.Version) {
+				if scanned.Service != "" {
+					service.Service = scanned.Service
+				}
+				if scanned.Description != "" {
+					service.Version = scanned.Description
+				}
+				step.plan.Updated = append(step.plan.Updated, service)
+			}
+		}
+		if step.asset != nil && (len(step.plan.NewServices) > 0 || len(step.plan.Updated) > 0) {
+			step.plan.Action = "update"
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+func importPlans(steps []importStep) []models.ImportHostPlan {
+	plans := make([]models.ImportHostPlan, len(steps))
+	for i, step := range steps {
+		plans[i] = step.plan
+	}
+	return plans
+}
+
+// applyImportStep creates or updates the host's asset and files a draft report for every new service.
+func applyImportStep(tx *gorm.DB, scan models.ScanImport, assignment models.DemonVictim, step importStep) ([]models.Report, error) {
+	host := step.host
+	asset := step.asset
+	if asset == nil {
+		asset = &models.Asset{
+			VictimID:       scan.VictimID,
+			DiscoveredByID: scan.DemonID,
+			Hostname:       host.Hostname,
+			Address:        host.Address,
+			OS:             host.OS,
+			Notes:          fmt.Sprintf("Imported from scan #%d", scan.ID),
+		}
+		if err := tx.Omit(clause.Associations).Create(asset).Error; err != nil {
+			return nil, err
+		}
+	} else {
+		updates := map[string]interface{}{}
+		if asset.Hostname == "" && host.Hostname != "" {
+			updates["hostname"] = host.Hostname
+		}
+		if asset.Address == "" && host.Address != "" {
+			updates["address"] = host.Address
+		}
+		if host.OS != "" && host.OS != asset.OS {
+			updates["os"] = host.OS
+		}
+		if len(updates) > 0 {
+			if err := tx.Model(&models.Asset{}).Where("id = ?", asset.ID).Updates(updates).Error; err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, service := range step.plan.Updated {
+		if err := tx.Model(&models.AssetService{}).Where("id = ?", service.ID).
+			Updates(map[string]interface{}{"service": service.Service, "version": service.Version}).Error; err != nil {
+			return nil, err
+		}
+	}
+	if len(step.plan.NewServices) == 0 {
+		return nil, nil
+	}
+	services := make([]models.AssetService, len(step.plan.NewServices))
+	copy(services, step.plan.NewServices)
+	for i := range services {
+		services[i].AssetID = asset.ID
+	}
+	if err := tx.Create(&services).Error; err != nil {
+		return nil, err
+	}
+
+	var reports []models.Report
+	for i, service := range services {
+		description := fmt.Sprintf("Nmap found %s listening on %d/%s of %s.", service.Service, service.Port, service.Protocol, hostLabel(host))
+		if service.Service == "" {
+			description = fmt.Sprintf("Nmap found an open port %d/%s on %s.", service.Port, service.Protocol, hostLabel(host))
+		}
+		if service.Version != "" {
+			description += " Detected: " + service.Version + "."
+		}
+		description += fmt.Sprintf(" Imported from scan #%d.", scan.ID)
+
+		report := models.Report{
+			DemonID:      scan.DemonID,
+			VictimID:     scan.VictimID,
+			AssignmentID: &assignment.ID,
+			Title:        step.plan.Drafts[i],
+			Description:  description,
+			Status:       models.ReportStatusDraft,
+		}
+		if err := tx.Create(&report).Error; err != nil {
+			return nil, err
+		}
+		if err := linkReportAssets(tx, report.ID, []models.Asset{*asset}); err != nil {
+			return nil, err
+		}
+		if _, err := recordRevision(tx, report, scan.DemonID); err != nil {
+			return nil, err
+		}
+		if err := recordReportStatus(tx, report.ID, "", report.Status, scan.DemonID, fmt.Sprintf("Drafted from scan #%d", scan.ID)); err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// CreateScanImport uploads an Nmap XML report (form field "file") for the demon's victim in the :id
+// parameter and previews what committing it would change.
+func CreateScanImport(c *gin.Context) {
+	assignment, ok := myAssignment(c)
+	if !ok {
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+
+	maxBytes := int64(config.EnvInt("IMPORT_MAX_BYTES", 5<<20))
+	// Leave room for the multipart envelope around the file
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes+1<<20)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Scan files cannot exceed %d bytes", maxBytes)})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A file is required in the \"file\" form field"})
+		return
+	}
+	if header.Size > maxBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Scan files cannot exceed %d bytes", maxBytes)})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read uploaded file"})
+		return
+	}
+	defer file.Close()
+
+	run, err := nmap.Parse(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The file is not a valid Nmap XML report (nmap -oX)"})
+		return
+	}
+
+	hosts, skipped := scanHosts(run)
+	if len(hosts) == 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "The scan has no usable hosts that were up", "skipped": skipped})
+		return
+	}
+
+	scan := models.ScanImport{
+		VictimID: assignment.VictimID,
+		DemonID:  user.ID,
+		FileName: filepath.Base(header.Filename),
+		Scanner:  "nmap " + run.Version,
+		ScanArgs: run.Args,
+		Hosts:    hosts,
+		Status:   models.ScanImportPreview,
+	}
+	if !run.Start.IsZero() {
+		scan.ScannedAt = &run.Start
+	}
+
+	steps, err := planScanImport(config.DB, scan.VictimID, hosts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to preview import"})
+		return
+	}
+	if err := config.DB.Create(&scan).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save import"})
+		return
+	}
+
+	if skipped == nil {
+		skipped = []string{}
+	}
+	c.JSON(http.StatusCreated, gin.H{"import": scan, "plan": importPlans(steps), "skipped": skipped})
+}
+
+// scanImportForUser loads one of the demon's imports from the :id parameter.
+func scanImportForUser(c *gin.Context) (models.ScanImport, bool) {
+	var scan models.ScanImport
+
+	importID := c.Param("id")
+	id, err := strconv.ParseUint(importID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid import ID"})
+		return scan, false
+	}
+
+	user := c.MustGet("user").(models.User)
+
+	if err := config.DB.Where("id = ? AND demon_id = ?", id, user.ID).First(&scan).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Import not found"})
+		return scan, false
+	}
+	return scan, true
+}
+
+func GetMyScanImports(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	var imports []models.ScanImport
+	if err := config.DB.Where("demon_id = ?", user.ID).Omit("hosts").Order("created_at DESC").Find(&imports).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch imports"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"imports": imports})
+}
+
+// GetScanImport returns an import; imports not yet committed come with a fresh preview of their plan.
+func GetScanImport(c *gin.Context) {
+	scan, ok := scanImportForUser(c)
+	if !ok {
+		return
+	}
+
+	if scan.Status != models.ScanImportPreview {
+		c.JSON(http.StatusOK, gin.H{"import": scan})
+		return
+	}
+
+	steps, err := planScanImport(config.DB, scan.VictimID, scan.Hosts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to preview import"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"import": scan, "plan": importPlans(steps)})
+}
+
+// CommitScanImport applies an import: assets are created or updated and draft reports are filed for new services.
+func CommitScanImport(c *gin.Context) {
+	scan, ok := scanImportForUser(c)
+	if !ok {
+		return
+	}
+
+	assignment, err := activeAssignment(config.DB, scan.DemonID, scan.VictimID)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Assign this victim before importing scans of them"})
+		return
+	}
+
+	var steps []importStep
+	reports := []models.Report{}
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&scan, scan.ID).Error; err != nil {
+			return err
+		}
+		if scan.Status != models.ScanImportPreview {
+			return errImportCommitted
+		}
+
+		// Serialize imports of the same victim so they do not create the same asset twice
+		var victim models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&victim, scan.VictimID).Error; err != nil {
+			return err
+		}
+
+		var err error
+		if steps, err = planScanImport(tx, scan.VictimID, scan.Hosts); err != nil {
+			return err
+		}
+		for _, step := range steps {
+			drafted, err := applyImportStep(tx, scan, assignment, step)
+			if err != nil {
+				return err
+			}
+			reports = append(reports, drafted...)
+		}
+
+		now := time.Now()
+		scan.Status = models.ScanImportCommitted
+		scan.CommittedAt = &now
+		return tx.Omit(clause.Associations).Save(&scan).Error
+	})
+	if errors.Is(err, errImportCommitted) {
+		c.JSON(http.StatusConflict, gin.H{"error": "This import has already been committed"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit import"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"import": scan, "plan": importPlans(steps), "reports": reports})
+}
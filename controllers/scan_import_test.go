@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"os"
+	"testing"
+
+	"andrei-api/models"
+	"andrei-api/nmap"
+)
+
+func TestScanHostsMergesDuplicates(t *testing.T) {
+	file, err := os.Open("../nmap/testdata/scan.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	run, err := nmap.Parse(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hosts, skipped := scanHosts(run)
+	if len(skipped) != 0 {
+		t.Errorf("skipped %v", skipped)
+	}
+	if len(hosts) != 2 {
+		t.Fatalf("got %d hosts, want the duplicate 10.0.0.5 merged into 2", len(hosts))
+	}
+
+	web := hosts[0]
+	if web.Address != "10.0.0.5" || web.Hostname != "web.corp.local" {
+		t.Errorf("host %s", hostLabel(web))
+	}
+	want := []models.ScanService{
+		{Port: 22, Protocol: "tcp", Service: "ssh", Description: "OpenSSH 8.9p1 (Ubuntu Linux; protocol 2.0)"},
+		{Port: 80, Protocol: "tcp", Service: "http", Description: "nginx"},
+		{Port: 8080, Protocol: "tcp", Service: "http-proxy"},
+	}
+	if len(web.Services) != len(want) {
+		t.Fatalf("services %+v, want %+v", web.Services, want)
+	}
+	for i := range want {
+		if web.Services[i] != want[i] {
+			t.Errorf("service %d: %+v, want %+v", i, web.Services[i], want[i])
+		}
+	}
+}
+
+func TestScanHostsMergesHostnameAlias(t *testing.T) {
+	run := &nmap.Run{Hosts: []nmap.Host{
+		{Hostnames: []string{"DB.corp.local."}, Ports: []nmap.Port{{Protocol: "tcp", Port: 5432, Service: "postgresql"}}},
+		{Address: "10.0.0.7", Hostnames: []string{"db.corp.local"}, OS: "Linux", Ports: []nmap.Port{
+			{Protocol: "tcp", Port: 5432, Product: "PostgreSQL"},
+			{Protocol: "tcp", Port: 5432},
+			{Protocol: "tcp", Port: 22, Service: "ssh"},
+		}},
+		// Another address for the same name is another asset
+		{Address: "10.0.0.8", Hostnames: []string{"db.corp.local"}},
+	}}
+
+	hosts, skipped := scanHosts(run)
+	if len(skipped) != 0 {
+		t.Errorf("skipped %v", skipped)
+	}
+	if len(hosts) != 2 {
+		t.Fatalf("got %d hosts, want 2", len(hosts))
+	}
+	db := hosts[0]
+	if db.Address != "10.0.0.7" || db.Hostname != "db.corp.local" || db.OS != "Linux" {
+		t.Errorf("merged host %+v", db)
+	}
+	if len(db.Services) != 2 || db.Services[0].Service != "postgresql" || db.Services[0].Description != "PostgreSQL" {
+		t.Errorf("merged services %+v", db.Services)
+	}
+	if hosts[1].Address != "10.0.0.8" {
+		t.Errorf("second host %+v", hosts[1])
+	}
+}
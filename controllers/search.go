@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"html"
+	"net/http"
+	"strings"
+
+	"andrei-api/config"
+	"andrei-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Highlights are marked with control characters, stripped from the text beforehand, so the report text
+// can be HTML-escaped before the markers become <mark> tags.
+const (
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+)
+
+const (
+	highlightMarkers = `StartSel="` + highlightStart + `", StopSel="` + highlightStop + `"`
+	titleOptions     = highlightMarkers + ", HighlightAll=true"
+	headlineOptions  = highlightMarkers + ", MaxFragments=2, MaxWords=30, MinWords=10, FragmentDelimiter=\" … \""
+)
+
+var highlightTags = strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>")
+
+// highlightHTML escapes a ts_headline result and turns its highlight markers into <mark> tags.
+func highlightHTML(headline string) string {
+	return highlightTags.Replace(html.EscapeString(headline))
+}
+
+// searchReports runs the ?q= full-text search (web search syntax: quotes, OR, -word) over the given reports,
+// most relevant first.
+func searchReports(c *gin.Context, query *gorm.DB) {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter q is required"})
+		return
+	}
+
+	tsquery := "websearch_to_tsquery('" + config.SearchLanguage + "', @q)"
+	query = query.Where("reports.search_vector @@ "+tsquery, map[string]interface{}{"q": q})
+	// Share the filters between the count and the page query
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search reports"})
+		return
+	}
+
+	page, limit, offset := pagination(c)
+	var results []models.ReportSearchResult
+	err := query.Select(`reports.id, reports.demon_id, reports.victim_id, reports.title, reports.status, reports.created_at,
+			ts_rank(reports.search_vector, `+tsquery+`) AS rank,
+			ts_headline('`+config.SearchLanguage+`', translate(reports.title, @markers, ''), `+tsquery+`, @title_options) AS title_highlight,
+			ts_headline('`+config.SearchLanguage+`', translate(reports.description, @markers, ''), `+tsquery+`, @options) AS snippet`,
+		map[string]interface{}{"q": q, "markers": highlightStart + highlightStop, "title_options": titleOptions, "options": headlineOptions}).
+		Order("rank DESC, reports.created_at DESC").Limit(limit).Offset(offset).Scan(&results).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search reports"})
+		return
+	}
+	for i := range results {
+		results[i].TitleHighlight = highlightHTML(results[i].TitleHighlight)
+		results[i].Snippet = highlightHTML(results[i].Snippet)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"query": q, "results": results, "page": page, "limit": limit, "total": total})
+}
+
+func SearchReports(c *gin.Context) {
+	query, ok := filterReports(c, config.DB.Model(&models.Report{}))
+	if !ok {
+		return
+	}
+	searchReports(c, query)
+}
+
+func SearchMyReports(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	searchReports(c, config.DB.Model(&models.Report{}).Where("reports.demon_id = ?", user.ID))
+}
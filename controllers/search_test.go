@@ -0,0 +1,17 @@
+package controllers
+
+import "testing"
+
+func TestHighlightHTML(t *testing.T) {
+	tests := map[string]string{
+		"plain text":                          "plain text",
+		"found \x02admin\x03 password":        "found <mark>admin</mark> password",
+		"<script>alert(1)</script> \x02x\x03": "&lt;script&gt;alert(1)&lt;/script&gt; <mark>x</mark>",
+		`<b>bold</b> & "quoted"`:              "&lt;b&gt;bold&lt;/b&gt; &amp; &#34;quoted&#34;",
+	}
+	for headline, want := range tests {
+		if got := highlightHTML(headline); got != want {
+			t.Errorf("highlightHTML(%q) = %q, want %q", headline, got, want)
+		}
+	}
+}
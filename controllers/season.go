@@ -0,0 +1,255 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"andrei-api/config"
+	"andrei-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// closeSeason freezes the season window and archives its final standings. Seasons are closed lazily by
+// concurrent requests, so the season row is locked and a season closed in the meantime is left as is.
+func closeSeason(season *models.Season) error {
+	formula, err := loadScoringFormula()
+	if err != nil {
+		return err
+	}
+
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(season, season.ID).Error; err != nil {
+			return err
+		}
+		if season.Status != models.SeasonStatusActive {
+			return nil
+		}
+
+		now := time.Now()
+		if season.EndsAt == nil || season.EndsAt.After(now) {
+			season.EndsAt = &now
+		}
+		season.Status = models.SeasonStatusClosed
+		season.ClosedAt = &now
+
+		entries, err := rankDemons(tx, season, formula, 0, 0)
+		if err != nil {
+			return err
+		}
+
+		standings := make([]models.SeasonStanding, 0, len(entries))
+		for _, entry := range entries {
+			standings = append(standings, models.SeasonStanding{
+				SeasonID:         season.ID,
+				DemonID:          entry.DemonID,
+				Rank:             entry.Rank,
+				Score:            entry.Score,
+				VictimsCount:     entry.VictimsCount,
+				RewardsCount:     entry.RewardsCount,
+				PunishmentsCount: entry.PunishmentsCount,
+				TotalPoints:      entry.TotalPoints,
+				ReportsCount:     entry.ReportsCount,
+			})
+		}
+		if len(standings) > 0 {
+			if err := tx.Omit("Demon").Create(&standings).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Save(season).Error
+	})
+}
+
+// closeExpiredSeasons archives active seasons whose end date has already passed.
+func closeExpiredSeasons() error {
+	var expired []models.Season
+	if err := config.DB.Where("status = ? AND ends_at IS NOT NULL AND ends_at <= ?", models.SeasonStatusActive, time.Now()).
+		Find(&expired).Error; err != nil {
+		return err
+	}
+
+	for i := range expired {
+		if err := closeSeason(&expired[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// currentSeason returns the active season, or nil when no season is running.
+func currentSeason() (*models.Season, error) {
+	if err := closeExpiredSeasons(); err != nil {
+		return nil, err
+	}
+
+	var season models.Season
+	err := config.DB.Where("status = ?", models.SeasonStatusActive).Order("starts_at DESC").First(&season).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &season, nil
+}
+
+// leaderboard returns one page of the archived standings of a closed season,
+// or of the live ranking of an active one.
+func leaderboard(season models.Season, limit, offset int) ([]models.RankingEntry, error) {
+	if season.Status != models.SeasonStatusClosed {
+		formula, err := loadScoringFormula()
+		if err != nil {
+			return nil, err
+		}
+		return rankDemons(config.DB, &season, formula, limit, offset)
+	}
+
+	var standings []models.SeasonStanding
+	query := config.DB.Where("season_id = ?", season.ID).Preload("Demon").Order("rank ASC, demon_id ASC")
+	if limit > 0 {
+		query = query.Limit(limit).Offset(offset)
+	}
+	if err := query.Find(&standings).Error; err != nil {
+		return nil, err
+	}
+
+	entries := make([]models.RankingEntry, 0, len(standings))
+	for _, standing := range standings {
+		entries = append(entries, models.RankingEntry{
+			Rank:             standing.Rank,
+			DemonID:          standing.DemonID,
+			Username:         standing.Demon.Username,
+			Score:            standing.Score,
+			VictimsCount:     standing.VictimsCount,
+			RewardsCount:     standing.RewardsCount,
+			PunishmentsCount: standing.PunishmentsCount,
+			TotalPoints:      standing.TotalPoints,
+			ReportsCount:     standing.ReportsCount,
+		})
+	}
+	return entries, nil
+}
+
+func CreateSeason(c *gin.Context) {
+	var input models.SeasonCreate
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	startsAt := time.Now()
+	if input.StartsAt != nil {
+		startsAt = *input.StartsAt
+	}
+	if input.EndsAt != nil && !input.EndsAt.After(startsAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ends_at must be after starts_at"})
+		return
+	}
+
+	active, err := currentSeason()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch current season"})
+		return
+	}
+	if active != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Another season is still active, close it first"})
+		return
+	}
+
+	season := models.Season{
+		Name:     input.Name,
+		StartsAt: startsAt,
+		EndsAt:   input.EndsAt,
+		Status:   models.SeasonStatusActive,
+	}
+
+	if err := config.DB.Create(&season).Error; err != nil {
+		// Another season was started since the check above
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Another season is still active, close it first"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create season"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"season": season})
+}
+
+func GetSeasons(c *gin.Context) {
+	if err := closeExpiredSeasons(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to close expired seasons"})
+		return
+	}
+
+	var seasons []models.Season
+	if err := config.DB.Order("starts_at DESC").Find(&seasons).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch seasons"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"seasons": seasons})
+}
+
+func CloseSeason(c *gin.Context) {
+	seasonID := c.Param("id")
+	id, err := strconv.ParseUint(seasonID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID"})
+		return
+	}
+
+	var season models.Season
+	if err := config.DB.First(&season, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Season not found"})
+		return
+	}
+
+	if season.Status == models.SeasonStatusClosed {
+		c.JSON(http.StatusConflict, gin.H{"error": "Season is already closed"})
+		return
+	}
+
+	if err := closeSeason(&season); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to close season"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"season": season})
+}
+
+func GetSeasonLeaderboard(c *gin.Context) {
+	seasonID := c.Param("id")
+	id, err := strconv.ParseUint(seasonID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID"})
+		return
+	}
+
+	if err := closeExpiredSeasons(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to close expired seasons"})
+		return
+	}
+
+	var season models.Season
+	if err := config.DB.First(&season, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Season not found"})
+		return
+	}
+
+	page, limit, offset := pagination(c)
+	entries, err := leaderboard(season, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch leaderboard"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"season": season, "leaderboard": entries, "page": page, "limit": limit})
+}
@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"andrei-api/cvss"
+	"andrei-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// applyCVSS sets the report's CVSS vector with its base score and severity. An empty vector clears them.
+func applyCVSS(report *models.Report, vector string) error {
+	vector = strings.TrimSpace(vector)
+	if vector == "" {
+		report.CVSSVector, report.CVSSScore, report.Severity = "", nil, ""
+		return nil
+	}
+
+	parsed, err := cvss.Parse(vector)
+	if err != nil {
+		return err
+	}
+	score := parsed.BaseScore()
+	report.CVSSVector = parsed.String()
+	report.CVSSScore = &score
+	report.Severity = cvss.SeverityOf(score)
+	return nil
+}
+
+// filterReportSeverity applies the severity (comma separated) and min_score query filters.
+// It writes a 400 response and returns false when a filter is invalid.
+func filterReportSeverity(c *gin.Context, query *gorm.DB) (*gorm.DB, bool) {
+	if value := c.Query("severity"); value != "" {
+		severities := strings.Split(strings.ToLower(value), ",")
+		for _, severity := range severities {
+			valid := false
+			for _, known := range cvss.Severities {
+				valid = valid || severity == known
+			}
+			if !valid {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid severity. Use " + strings.Join(cvss.Severities, ", ")})
+				return nil, false
+			}
+		}
+		query = query.Where("reports.severity IN ?", severities)
+	}
+
+	if value := c.Query("min_score"); value != "" {
+		score, err := strconv.ParseFloat(value, 64)
+		if err != nil || score < 0 || score > 10 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid min_score, use a number between 0 and 10"})
+			return nil, false
+		}
+		query = query.Where("reports.cvss_score >= ?", score)
+	}
+	return query, true
+}
+
+// reportOrder reads ?sort=: severity (most severe first), severity_asc, or the given default order.
+// Reports without a CVSS vector come last either way.
+func reportOrder(c *gin.Context, fallback string) (string, bool) {
+	switch c.Query("sort") {
+	case "":
+		return fallback, true
+	case "severity":
+		return "reports.cvss_score DESC NULLS LAST, reports.created_at DESC", true
+	case "severity_asc":
+		return "reports.cvss_score ASC NULLS LAST, reports.created_at DESC", true
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort. Use severity or severity_asc"})
+	return "", false
+}
+
+// severityCounts counts the demon's submitted reports per severity, with every severity present.
+func severityCounts(db *gorm.DB, demonID uint) (map[string]int64, error) {
+	var rows []struct {
+		Severity string
+		Count    int64
+	}
+	if err := db.Model(&models.Report{}).Where("demon_id = ? AND severity <> '' AND status NOT IN ?", demonID, uncountedReportStatuses).
+		Select("severity, COUNT(*) AS count").Group("severity").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(cvss.Severities))
+	for _, severity := range cvss.Severities {
+		counts[severity] = 0
+	}
+	for _, row := range rows {
+		counts[row.Severity] = row.Count
+	}
+	return counts, nil
+}
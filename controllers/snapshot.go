@@ -0,0 +1,290 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"andrei-api/config"
+	"andrei-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// takeRankingSnapshot stores the current default ranking (active season, or all-time) for every demon.
+func takeRankingSnapshot() ([]models.RankingSnapshot, error) {
+	season, err := currentSeason()
+	if err != nil {
+		return nil, err
+	}
+	formula, err := loadScoringFormula()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := rankDemons(config.DB, season, formula, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	takenAt := time.Now()
+	snapshots := make([]models.RankingSnapshot, 0, len(entries))
+	for _, entry := range entries {
+		snapshot := models.RankingSnapshot{
+			TakenAt:          takenAt,
+			DemonID:          entry.DemonID,
+			Rank:             entry.Rank,
+			Score:            entry.Score,
+			VictimsCount:     entry.VictimsCount,
+			RewardsCount:     entry.RewardsCount,
+			PunishmentsCount: entry.PunishmentsCount,
+			TotalPoints:      entry.TotalPoints,
+			ReportsCount:     entry.ReportsCount,
+		}
+		if season != nil {
+			snapshot.SeasonID = &season.ID
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	if len(snapshots) > 0 {
+		if err := config.DB.Create(&snapshots).Error; err != nil {
+			return nil, err
+		}
+	}
+	return snapshots, nil
+}
+
+// StartRankingSnapshots snapshots the ranking every RANKING_SNAPSHOT_INTERVAL_HOURS hours
+// (default 24, 0 disables it). A snapshot is taken right away when the last one is too old.
+func StartRankingSnapshots() {
+	hours := config.EnvInt("RANKING_SNAPSHOT_INTERVAL_HOURS", 24)
+	if hours <= 0 {
+		log.Println("Ranking snapshots disabled")
+		return
+	}
+	interval := time.Duration(hours) * time.Hour
+
+	go func() {
+		var last models.RankingSnapshot
+		if err := config.DB.Order("taken_at DESC").First(&last).Error; err != nil || time.Since(last.TakenAt) >= interval {
+			if _, err := takeRankingSnapshot(); err != nil {
+				log.Printf("Failed to take ranking snapshot: %v", err)
+			}
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if _, err := takeRankingSnapshot(); err != nil {
+				log.Printf("Failed to take ranking snapshot: %v", err)
+			}
+		}
+	}()
+}
+
+// snapshotSeason reads ?season_id= for rank movements, defaulting to the current season. Without a
+// current season, movements compare the all-time snapshots taken outside any season (nil).
+// It writes an error response and returns false when the season cannot be resolved.
+func snapshotSeason(c *gin.Context) (*uint, bool) {
+	if value := c.Query("season_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID"})
+			return nil, false
+		}
+		var season models.Season
+		if err := config.DB.Select("id").First(&season, id).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Season not found"})
+			return nil, false
+		}
+		return &season.ID, true
+	}
+
+	season, err := currentSeason()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch current season"})
+		return nil, false
+	}
+	if season == nil {
+		return nil, true
+	}
+	return &season.ID, true
+}
+
+// rankMovements compares each demon's first and last snapshot within [from, to) of one season, since
+// ranks and scores restart with every season. A nil season compares the all-time snapshots.
+func rankMovements(seasonID *uint, from, to time.Time) ([]models.RankMovement, error) {
+	edgeQuery := `
+SELECT DISTINCT ON (ranking_snapshots.demon_id) ranking_snapshots.*
+FROM ranking_snapshots
+WHERE taken_at >= @from AND taken_at < @to AND `
+	if seasonID != nil {
+		edgeQuery += "season_id = @season"
+	} else {
+		edgeQuery += "season_id IS NULL"
+	}
+	edgeQuery += `
+ORDER BY ranking_snapshots.demon_id, taken_at `
+	args := map[string]interface{}{"from": from, "to": to, "season": seasonID}
+
+	var first, last []models.RankingSnapshot
+	if err := config.DB.Raw(edgeQuery+"ASC", args).Scan(&first).Error; err != nil {
+		return nil, err
+	}
+	if err := config.DB.Raw(edgeQuery+"DESC", args).Scan(&last).Error; err != nil {
+		return nil, err
+	}
+
+	var demons []models.User
+	if err := config.DB.Select("id", "username").Where("role = ?", models.RoleDemon).Find(&demons).Error; err != nil {
+		return nil, err
+	}
+	usernames := make(map[uint]string, len(demons))
+	for _, demon := range demons {
+		usernames[demon.ID] = demon.Username
+	}
+
+	earliest := make(map[uint]models.RankingSnapshot, len(first))
+	for _, snapshot := range first {
+		earliest[snapshot.DemonID] = snapshot
+	}
+
+	movements := []models.RankMovement{}
+	for _, latest := range last {
+		start, ok := earliest[latest.DemonID]
+		username, active := usernames[latest.DemonID]
+		if !ok || !active {
+			continue
+		}
+		movements = append(movements, models.RankMovement{
+			DemonID:       latest.DemonID,
+			Username:      username,
+			FromRank:      start.Rank,
+			ToRank:        latest.Rank,
+			RankChange:    start.Rank - latest.Rank,
+			PointsChange:  latest.TotalPoints - start.TotalPoints,
+			ReportsChange: latest.ReportsCount - start.ReportsCount,
+			FromTakenAt:   start.TakenAt,
+			ToTakenAt:     latest.TakenAt,
+		})
+	}
+
+	sort.SliceStable(movements, func(i, j int) bool {
+		return movements[i].RankChange > movements[j].RankChange
+	})
+	return movements, nil
+}
+
+func CreateRankingSnapshot(c *gin.Context) {
+	snapshots, err := takeRankingSnapshot()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to take ranking snapshot"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"snapshots": snapshots})
+}
+
+func GetRankMovement(c *gin.Context) {
+	from, to, ok := timeRange(c, 30)
+	if !ok {
+		return
+	}
+	seasonID, ok := snapshotSeason(c)
+	if !ok {
+		return
+	}
+
+	movements, err := rankMovements(seasonID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute rank movement"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"from": from, "to": to, "season_id": seasonID, "movements": movements})
+}
+
+func GetTopClimbers(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "7"))
+	if err != nil || days < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid days"})
+		return
+	}
+	_, limit, _ := pagination(c)
+	seasonID, ok := snapshotSeason(c)
+	if !ok {
+		return
+	}
+
+	to := time.Now()
+	from := to.AddDate(0, 0, -days)
+	movements, err := rankMovements(seasonID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute rank movement"})
+		return
+	}
+
+	climbers := []models.RankMovement{}
+	for _, movement := range movements {
+		if movement.RankChange <= 0 || len(climbers) == limit {
+			break
+		}
+		climbers = append(climbers, movement)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"from": from, "to": to, "season_id": seasonID, "climbers": climbers})
+}
+
+func demonTimeline(c *gin.Context, demonID uint) {
+	from, to, ok := timeRange(c, 90)
+	if !ok {
+		return
+	}
+
+	var snapshots []models.RankingSnapshot
+	if err := config.DB.Where("demon_id = ? AND taken_at >= ? AND taken_at < ?", demonID, from, to).
+		Order("taken_at ASC").Find(&snapshots).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch timeline"})
+		return
+	}
+
+	var timeline []gin.H
+	for _, snapshot := range snapshots {
+		timeline = append(timeline, gin.H{
+			"taken_at":      snapshot.TakenAt,
+			"season_id":     snapshot.SeasonID,
+			"rank":          snapshot.Rank,
+			"score":         snapshot.Score,
+			"total_points":  snapshot.TotalPoints,
+			"reports_count": snapshot.ReportsCount,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"demon_id": demonID, "from": from, "to": to, "timeline": timeline})
+}
+
+func GetDemonTimeline(c *gin.Context) {
+	demonID := c.Param("id")
+	id, err := strconv.ParseUint(demonID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid demon ID"})
+		return
+	}
+
+	var demon models.User
+	if err := config.DB.Where("id = ? AND role = ?", id, models.RoleDemon).First(&demon).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Demon not found"})
+		return
+	}
+
+	demonTimeline(c, demon.ID)
+}
+
+func GetMyTimeline(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	demonTimeline(c, user.ID)
+}
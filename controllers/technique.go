@@ -0,0 +1,266 @@
+package controllers
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"andrei-api/attack"
+	"andrei-api/config"
+	"andrei-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// resolveTechniques normalizes and deduplicates technique IDs, returning those missing from the taxonomy.
+func resolveTechniques(ids []string) ([]string, []string) {
+	resolved := []string{}
+	unknown := []string{}
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		id = attack.Normalize(id)
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if _, ok := attack.Lookup(id); !ok {
+			unknown = append(unknown, id)
+			continue
+		}
+		resolved = append(resolved, id)
+	}
+	return resolved, unknown
+}
+
+// linkReportTechniques replaces the techniques a report is tagged with.
+func linkReportTechniques(tx *gorm.DB, reportID uint, ids []string) ([]models.ReportTechnique, error) {
+	if err := tx.Where("report_id = ?", reportID).Delete(&models.ReportTechnique{}).Error; err != nil {
+		return nil, err
+	}
+	tags := make([]models.ReportTechnique, len(ids))
+	if len(ids) == 0 {
+		return tags, nil
+	}
+	for i, id := range ids {
+		tags[i] = models.ReportTechnique{ReportID: reportID, TechniqueID: id}
+	}
+	if err := tx.Create(&tags).Error; err != nil {
+		return nil, err
+	}
+	withTechniqueNames(tags)
+	return tags, nil
+}
+
+// withTechniqueNames fills in the taxonomy names of loaded technique tags.
+func withTechniqueNames(tags []models.ReportTechnique) {
+	for i := range tags {
+		if technique, ok := attack.Lookup(tags[i].TechniqueID); ok {
+			tags[i].Name = technique.Name
+		}
+	}
+}
+
+// withReportTechniqueNames fills in the technique names of a list of reports.
+func withReportTechniqueNames(reports []models.Report) {
+	for i := range reports {
+		withTechniqueNames(reports[i].Techniques)
+	}
+}
+
+// filterReportTechnique applies the technique query filter (comma separated). A technique also matches
+// the reports tagged with one of its sub-techniques.
+// It writes a 400 response and returns false when a technique is unknown.
+func filterReportTechnique(c *gin.Context, query *gorm.DB) (*gorm.DB, bool) {
+	value := c.Query("technique")
+	if value == "" {
+		return query, true
+	}
+
+	ids, unknown := resolveTechniques(strings.Split(value, ","))
+	if len(unknown) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown techniques", "techniques": unknown})
+		return nil, false
+	}
+	for _, id := range ids {
+		ids = append(ids, attack.SubTechniques(id)...)
+	}
+	return query.Where("EXISTS (SELECT 1 FROM report_techniques WHERE report_techniques.report_id = reports.id AND report_techniques.technique_id IN ?)", ids), true
+}
+
+// GetTechniques lists the technique taxonomy, optionally restricted to a tactic or to the
+// techniques whose ID or name contains q.
+func GetTechniques(c *gin.Context) {
+	tactic := c.Query("tactic")
+	if tactic != "" && !attack.IsTactic(tactic) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown tactic"})
+		return
+	}
+	search := strings.ToLower(strings.TrimSpace(c.Query("q")))
+
+	techniques := []attack.Technique{}
+	for _, technique := range attack.Techniques() {
+		if tactic != "" && !containsString(technique.Tactics, tactic) {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(technique.ID+" "+technique.Name), search) {
+			continue
+		}
+		techniques = append(techniques, technique)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"version": attack.Version(), "tactics": attack.Tactics(), "techniques": techniques})
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// SetReportTechniques replaces the techniques the report in the :id parameter is tagged with.
+func SetReportTechniques(c *gin.Context) {
+	var input models.ReportTechniquesUpdate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+
+	report, ok := reportForUser(c, user)
+	if !ok {
+		return
+	}
+
+	ids, unknown := resolveTechniques(input.TechniqueIDs)
+	if len(unknown) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown techniques", "techniques": unknown})
+		return
+	}
+
+	var tags []models.ReportTechnique
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		// Lock the report so concurrent updates do not interleave their tags
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.Report{}, report.ID).Error; err != nil {
+			return err
+		}
+		var err error
+		tags, err = linkReportTechniques(tx, report.ID, ids)
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to tag report"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"report_id": report.ID, "techniques": tags})
+}
+
+// techniqueUsage counts, per technique, the submitted reports tagged with it and the demons who filed them,
+// most used first. demonID restricts the count to one demon's reports.
+func techniqueUsage(c *gin.Context, db *gorm.DB, demonID *uint) ([]models.TechniqueUsage, bool) {
+	query := db.Table("report_techniques").
+		Joins("JOIN reports ON reports.id = report_techniques.report_id AND reports.deleted_at IS NULL").
+		Select("report_techniques.technique_id, COUNT(DISTINCT reports.id) AS reports, "+
+			"COUNT(DISTINCT reports.demon_id) AS demons, MAX(reports.created_at) AS last_used_at").
+		Where("reports.status NOT IN ?", uncountedReportStatuses).
+		Group("report_techniques.technique_id").
+		Order("reports DESC, report_techniques.technique_id ASC")
+	if demonID != nil {
+		query = query.Where("reports.demon_id = ?", *demonID)
+	}
+
+	from, ok := dateParam(c, "from")
+	if !ok {
+		return nil, false
+	}
+	if from != nil {
+		query = query.Where("reports.created_at >= ?", *from)
+	}
+	to, ok := endDateParam(c, "to")
+	if !ok {
+		return nil, false
+	}
+	if to != nil {
+		query = query.Where("reports.created_at < ?", *to)
+	}
+
+	usage := []models.TechniqueUsage{}
+	if err := query.Scan(&usage).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute technique usage"})
+		return nil, false
+	}
+	for i := range usage {
+		usage[i].Tactics = []string{}
+		if technique, ok := attack.Lookup(usage[i].TechniqueID); ok {
+			usage[i].Name = technique.Name
+			usage[i].Tactics = technique.Tactics
+		}
+	}
+	return usage, true
+}
+
+// tacticUsage adds up the technique tags per tactic, with every tactic present.
+func tacticUsage(usage []models.TechniqueUsage) []gin.H {
+	counts := map[string]int64{}
+	for _, entry := range usage {
+		for _, tactic := range entry.Tactics {
+			counts[tactic] += entry.Reports
+		}
+	}
+
+	tactics := []gin.H{}
+	for _, tactic := range attack.Tactics() {
+		tactics = append(tactics, gin.H{"tactic": tactic.Slug, "name": tactic.Name, "tags": counts[tactic.Slug]})
+	}
+	sort.SliceStable(tactics, func(i, j int) bool { return tactics[i]["tags"].(int64) > tactics[j]["tags"].(int64) })
+	return tactics
+}
+
+func respondTechniqueStats(c *gin.Context, demonID *uint) {
+	usage, ok := techniqueUsage(c, config.DB, demonID)
+	if !ok {
+		return
+	}
+
+	response := gin.H{"techniques": usage, "tactics": tacticUsage(usage)}
+	if demonID != nil {
+		response["demon_id"] = *demonID
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetTechniqueStats shows how often each technique is used across the platform.
+func GetTechniqueStats(c *gin.Context) {
+	respondTechniqueStats(c, nil)
+}
+
+// GetDemonTechniqueStats shows the techniques used by the demon in the :id parameter.
+func GetDemonTechniqueStats(c *gin.Context) {
+	demonID := c.Param("id")
+	id, err := strconv.ParseUint(demonID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid demon ID"})
+		return
+	}
+
+	var demon models.User
+	if err := config.DB.Where("id = ? AND role = ?", id, models.RoleDemon).First(&demon).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Demon not found"})
+		return
+	}
+
+	respondTechniqueStats(c, &demon.ID)
+}
+
+// GetMyTechniqueStats shows the techniques the demon has used.
+func GetMyTechniqueStats(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+	respondTechniqueStats(c, &user.ID)
+}
@@ -0,0 +1,237 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"andrei-api/config"
+	"andrei-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var fieldNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,62}$`)
+
+// validateTemplateFields checks a template definition: unique snake_case names, known types, options for enums.
+func validateTemplateFields(fields []models.TemplateField) []string {
+	var problems []string
+	seen := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		if !fieldNamePattern.MatchString(field.Name) {
+			problems = append(problems, fmt.Sprintf("%s: field names must be lowercase letters, digits and underscores", field.Name))
+		}
+		if seen[field.Name] {
+			problems = append(problems, fmt.Sprintf("%s: duplicate field", field.Name))
+		}
+		seen[field.Name] = true
+
+		switch field.Type {
+		case models.TemplateFieldString, models.TemplateFieldNumber, models.TemplateFieldDate, models.TemplateFieldList:
+			if len(field.Options) > 0 {
+				problems = append(problems, fmt.Sprintf("%s: options are only allowed on enum fields", field.Name))
+			}
+		case models.TemplateFieldEnum:
+			if len(field.Options) == 0 {
+				problems = append(problems, fmt.Sprintf("%s: enum fields need options", field.Name))
+			}
+		default:
+			problems = append(problems, fmt.Sprintf("%s: unknown type %q, use string, number, enum, date or list", field.Name, field.Type))
+		}
+	}
+	return problems
+}
+
+func parseTemplateDate(value string) (time.Time, error) {
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// validateReportData checks report data against a template schema and returns the problems found.
+// Fields not defined by the template are rejected.
+func validateReportData(schema models.TemplateSchema, data models.JSONMap) []string {
+	var problems []string
+	defined := make(map[string]bool, len(schema.Fields))
+
+	for _, field := range schema.Fields {
+		defined[field.Name] = true
+		value, present := data[field.Name]
+		if !present || value == nil {
+			if field.Required {
+				problems = append(problems, field.Name+": is required")
+			}
+			continue
+		}
+
+		switch field.Type {
+		case models.TemplateFieldString:
+			if _, ok := value.(string); !ok {
+				problems = append(problems, field.Name+": must be a string")
+			}
+		case models.TemplateFieldNumber:
+			if number, ok := value.(float64); !ok || math.IsNaN(number) || math.IsInf(number, 0) {
+				problems = append(problems, field.Name+": must be a number")
+			}
+		case models.TemplateFieldEnum:
+			text, ok := value.(string)
+			valid := false
+			for _, option := range field.Options {
+				if ok && text == option {
+					valid = true
+					break
+				}
+			}
+			if !valid {
+				problems = append(problems, fmt.Sprintf("%s: must be one of %s", field.Name, strings.Join(field.Options, ", ")))
+			}
+		case models.TemplateFieldDate:
+			text, ok := value.(string)
+			if !ok {
+				problems = append(problems, field.Name+": must be a date (YYYY-MM-DD or RFC 3339)")
+			} else if _, err := parseTemplateDate(text); err != nil {
+				problems = append(problems, field.Name+": must be a date (YYYY-MM-DD or RFC 3339)")
+			}
+		case models.TemplateFieldList:
+			items, ok := value.([]interface{})
+			if ok {
+				for _, item := range items {
+					if _, isString := item.(string); !isString {
+						ok = false
+						break
+					}
+				}
+			}
+			if !ok {
+				problems = append(problems, field.Name+": must be a list of strings")
+			}
+		}
+	}
+
+	for name := range data {
+		if !defined[name] {
+			problems = append(problems, name+": is not a field of this template")
+		}
+	}
+	return problems
+}
+
+// filterReportData applies the ?template_id= filter and ?data.<field>=<value> filters on structured data.
+// It writes a 400 response and returns false when a filter is invalid.
+func filterReportData(c *gin.Context, query *gorm.DB) (*gorm.DB, bool) {
+	if value := c.Query("template_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template_id"})
+			return nil, false
+		}
+		query = query.Where("reports.template_id = ?", id)
+	}
+
+	for param, values := range c.Request.URL.Query() {
+		name, ok := strings.CutPrefix(param, "data.")
+		if !ok {
+			continue
+		}
+		if !fieldNamePattern.MatchString(name) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data filter " + param})
+			return nil, false
+		}
+		// Lists match when they contain the value, other fields when they are equal to it
+		query = query.Where(`(CASE jsonb_typeof(reports.data -> CAST(@name AS text))
+			WHEN 'array' THEN reports.data -> CAST(@name AS text) @> jsonb_build_array(CAST(@value AS text))
+			ELSE reports.data ->> CAST(@name AS text) = @value END)`, map[string]interface{}{"name": name, "value": values[0]})
+	}
+
+	return query, true
+}
+
+func CreateReportTemplate(c *gin.Context) {
+	var input models.ReportTemplateCreate
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if problems := validateTemplateFields(input.Fields); len(problems) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template fields", "details": problems})
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+
+	template := models.ReportTemplate{
+		Name:        input.Name,
+		Description: input.Description,
+		Schema:      models.TemplateSchema{Fields: input.Fields},
+		CreatedByID: user.ID,
+	}
+
+	if err := config.DB.Create(&template).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			c.JSON(http.StatusConflict, gin.H{"error": "A template with this name already exists"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create template"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"template": template})
+}
+
+func GetReportTemplates(c *gin.Context) {
+	var templates []models.ReportTemplate
+	if err := config.DB.Order("name ASC").Find(&templates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch templates"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"templates": templates})
+}
+
+func GetReportTemplate(c *gin.Context) {
+	templateID := c.Param("id")
+	id, err := strconv.ParseUint(templateID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+
+	var template models.ReportTemplate
+	if err := config.DB.First(&template, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"template": template})
+}
+
+// DeleteReportTemplate retires a template; reports already using it keep their data.
+func DeleteReportTemplate(c *gin.Context) {
+	templateID := c.Param("id")
+	id, err := strconv.ParseUint(templateID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+
+	result := config.DB.Delete(&models.ReportTemplate{}, id)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete template"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Template deleted successfully"})
+}
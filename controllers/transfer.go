@@ -0,0 +1,287 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"andrei-api/config"
+	"andrei-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	errInsufficientPoints = errors.New("insufficient points")
+	errTransferReviewed   = errors.New("transfer already reviewed")
+)
+
+// Transfer limits are configured through the environment:
+// TRANSFER_MIN_POINTS, TRANSFER_MAX_POINTS (0 disables the cap), TRANSFER_FEE_PERCENT
+// and TRANSFER_APPROVAL_THRESHOLD (amounts above it wait for andrei, 0 disables approval).
+func transferFee(amount int) int {
+	percent := config.EnvInt("TRANSFER_FEE_PERCENT", 0)
+	if percent <= 0 {
+		return 0
+	}
+	// Round up so small transfers are not free
+	return (amount*percent + 99) / 100
+}
+
+// availablePoints is the demon's balance minus the points held by transfers awaiting approval.
+// Callers must hold the lock on the demon row.
+func availablePoints(tx *gorm.DB, demonID uint) (int64, error) {
+	var balance, held int64
+	if err := tx.Model(&models.Reward{}).Where("demon_id = ?", demonID).Select("COALESCE(SUM(points), 0)").Scan(&balance).Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Model(&models.PointTransfer{}).Where("from_demon_id = ? AND status = ?", demonID, models.TransferStatusPendingApproval).
+		Select("COALESCE(SUM(amount + fee), 0)").Scan(&held).Error; err != nil {
+		return 0, err
+	}
+	return balance - held, nil
+}
+
+func lockDemon(tx *gorm.DB, demonID uint) error {
+	var demon models.User
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&demon, demonID).Error
+}
+
+// settleTransfer writes the debit and credit ledger entries of a transfer.
+func settleTransfer(tx *gorm.DB, transfer *models.PointTransfer) error {
+	debit := models.Reward{
+		DemonID:     transfer.FromDemonID,
+		Type:        models.RewardTypeTransfer,
+		Title:       "Points transferred",
+		Description: fmt.Sprintf("Transfer #%d to demon %d (fee %d)", transfer.ID, transfer.ToDemonID, transfer.Fee),
+		Points:      -(transfer.Amount + transfer.Fee),
+	}
+	if err := tx.Create(&debit).Error; err != nil {
+		return err
+	}
+
+	credit := models.Reward{
+		DemonID:     transfer.ToDemonID,
+		Type:        models.RewardTypeTransfer,
+		Title:       "Points received",
+		Description: fmt.Sprintf("Transfer #%d from demon %d", transfer.ID, transfer.FromDemonID),
+		Points:      transfer.Amount,
+	}
+	if err := tx.Create(&credit).Error; err != nil {
+		return err
+	}
+
+	transfer.Status = models.TransferStatusCompleted
+	transfer.DebitID = &debit.ID
+	transfer.CreditID = &credit.ID
+	return tx.Omit(clause.Associations).Save(transfer).Error
+}
+
+func CreateTransfer(c *gin.Context) {
+	var input models.TransferCreate
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+
+	if input.ToDemonID == user.ID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot transfer points to yourself"})
+		return
+	}
+
+	minPoints := config.EnvInt("TRANSFER_MIN_POINTS", 1)
+	maxPoints := config.EnvInt("TRANSFER_MAX_POINTS", 1000)
+	if input.Amount < minPoints {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Amount must be at least %d points", minPoints)})
+		return
+	}
+	if maxPoints > 0 && input.Amount > maxPoints {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Amount cannot exceed %d points", maxPoints)})
+		return
+	}
+
+	var recipient models.User
+	if err := config.DB.Where("id = ? AND role = ?", input.ToDemonID, models.RoleDemon).First(&recipient).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipient demon not found"})
+		return
+	}
+
+	transfer := models.PointTransfer{
+		FromDemonID: user.ID,
+		ToDemonID:   recipient.ID,
+		Amount:      input.Amount,
+		Fee:         transferFee(input.Amount),
+		Note:        input.Note,
+		Status:      models.TransferStatusPendingApproval,
+	}
+	threshold := config.EnvInt("TRANSFER_APPROVAL_THRESHOLD", 500)
+	needsApproval := threshold > 0 && input.Amount > threshold
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		// Serialize debits of the same demon so concurrent transfers cannot overdraw the balance
+		if err := lockDemon(tx, user.ID); err != nil {
+			return err
+		}
+
+		available, err := availablePoints(tx, user.ID)
+		if err != nil {
+			return err
+		}
+		if available < int64(transfer.Amount+transfer.Fee) {
+			return errInsufficientPoints
+		}
+
+		if err := tx.Create(&transfer).Error; err != nil {
+			return err
+		}
+		if needsApproval {
+			return nil
+		}
+		return settleTransfer(tx, &transfer)
+	})
+
+	if errors.Is(err, errInsufficientPoints) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Insufficient points for this transfer and its fee"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create transfer"})
+		return
+	}
+
+	if transfer.Status == models.TransferStatusCompleted {
+		refreshAchievements(transfer.ToDemonID)
+		c.JSON(http.StatusCreated, gin.H{"transfer": transfer})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"transfer": transfer, "message": "Transfer is above the approval threshold and awaits andrei's approval"})
+}
+
+func GetMyTransfers(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	var transfers []models.PointTransfer
+	if err := config.DB.Where("from_demon_id = ? OR to_demon_id = ?", user.ID, user.ID).
+		Preload("FromDemon").Preload("ToDemon").Order("created_at DESC").Find(&transfers).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch transfers"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"transfers": transfers})
+}
+
+func GetTransfers(c *gin.Context) {
+	query := config.DB.Preload("FromDemon").Preload("ToDemon").Order("created_at DESC")
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if demonID := c.Query("demon_id"); demonID != "" {
+		query = query.Where("from_demon_id = ? OR to_demon_id = ?", demonID, demonID)
+	}
+
+	var transfers []models.PointTransfer
+	if err := query.Find(&transfers).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch transfers"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"transfers": transfers})
+}
+
+// reviewTransfer locks a transfer awaiting approval and lets apply settle or reject it.
+func reviewTransfer(c *gin.Context, apply func(tx *gorm.DB, transfer *models.PointTransfer) error) {
+	transferID := c.Param("id")
+	id, err := strconv.ParseUint(transferID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transfer ID"})
+		return
+	}
+
+	var transfer models.PointTransfer
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&transfer, id).Error; err != nil {
+			return err
+		}
+		// Lock the sender first, like CreateTransfer does, then the transfer itself
+		if err := lockDemon(tx, transfer.FromDemonID); err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&transfer, id).Error; err != nil {
+			return err
+		}
+		if transfer.Status != models.TransferStatusPendingApproval {
+			return errTransferReviewed
+		}
+		return apply(tx, &transfer)
+	})
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Transfer not found"})
+		return
+	case errors.Is(err, errTransferReviewed):
+		c.JSON(http.StatusConflict, gin.H{"error": "Transfer has already been reviewed"})
+		return
+	case errors.Is(err, errInsufficientPoints):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Sender no longer has enough points for this transfer"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to review transfer"})
+		return
+	}
+
+	if transfer.Status == models.TransferStatusCompleted {
+		refreshAchievements(transfer.ToDemonID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"transfer": transfer})
+}
+
+func ApproveTransfer(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	reviewTransfer(c, func(tx *gorm.DB, transfer *models.PointTransfer) error {
+		// available already excludes the points held by this transfer, so it only has to stay non-negative
+		available, err := availablePoints(tx, transfer.FromDemonID)
+		if err != nil {
+			return err
+		}
+		if available < 0 {
+			return errInsufficientPoints
+		}
+
+		now := time.Now()
+		transfer.ReviewedByID = &user.ID
+		transfer.ReviewedAt = &now
+		return settleTransfer(tx, transfer)
+	})
+}
+
+func RejectTransfer(c *gin.Context) {
+	var input struct {
+		Reason string `json:"reason" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+
+	reviewTransfer(c, func(tx *gorm.DB, transfer *models.PointTransfer) error {
+		now := time.Now()
+		transfer.Status = models.TransferStatusRejected
+		transfer.Reason = input.Reason
+		transfer.ReviewedByID = &user.ID
+		transfer.ReviewedAt = &now
+		return tx.Omit(clause.Associations).Save(transfer).Error
+	})
+}
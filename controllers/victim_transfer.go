@@ -0,0 +1,466 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"andrei-api/config"
+	"andrei-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	errVictimTransferResolved = errors.New("victim transfer already resolved")
+	errTransferPending        = errors.New("victim already has a pending transfer")
+	errAssignmentGone         = errors.New("assignment is no longer active")
+	errDisputeResolved        = errors.New("dispute already resolved")
+	errDisputeOpen            = errors.New("victim already has an open dispute")
+	errNotContested           = errors.New("victim is not contested")
+	errNotClaimant            = errors.New("winner is not targeting the victim")
+)
+
+// notifyVictim tells a demon about a change to one of their assignments.
+func notifyVictim(tx *gorm.DB, userID uint, message string, assignmentID *uint) error {
+	return tx.Create(&models.Notification{
+		UserID:       userID,
+		Type:         models.NotificationTypeVictim,
+		Message:      message,
+		AssignmentID: assignmentID,
+	}).Error
+}
+
+// CreateVictimTransfer offers the demon's victim in the :id parameter to another demon.
+func CreateVictimTransfer(c *gin.Context) {
+	var input models.VictimTransferCreate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	assignment, ok := myAssignment(c)
+	if !ok {
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+
+	if input.ToDemonID == user.ID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot transfer a victim to yourself"})
+		return
+	}
+
+	var recipient models.User
+	if err := config.DB.Where("id = ? AND role = ?", input.ToDemonID, models.RoleDemon).First(&recipient).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipient demon not found"})
+		return
+	}
+
+	if _, err := activeAssignment(config.DB, recipient.ID, assignment.VictimID); err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "The recipient is already targeting this network admin", "code": "already_assigned"})
+		return
+	}
+
+	transfer := models.VictimTransfer{
+		AssignmentID: assignment.ID,
+		VictimID:     assignment.VictimID,
+		FromDemonID:  user.ID,
+		ToDemonID:    recipient.ID,
+		Note:         input.Note,
+		Status:       models.VictimTransferPending,
+	}
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		// Lock the assignment so only one transfer of it can be pending
+		var locked models.DemonVictim
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, assignment.ID).Error; err != nil {
+			return err
+		}
+		if locked.Status == models.AssignmentStatusAbandoned {
+			return errAssignmentGone
+		}
+
+		var pending int64
+		if err := tx.Model(&models.VictimTransfer{}).Where("assignment_id = ? AND status = ?", assignment.ID, models.VictimTransferPending).
+			Count(&pending).Error; err != nil {
+			return err
+		}
+		if pending > 0 {
+			return errTransferPending
+		}
+
+		if err := tx.Create(&transfer).Error; err != nil {
+			return err
+		}
+		message := fmt.Sprintf("%s wants to hand you one of their victims (transfer #%d)", user.Username, transfer.ID)
+		return notifyVictim(tx, recipient.ID, message, &assignment.ID)
+	})
+	switch {
+	case errors.Is(err, errAssignmentGone):
+		c.JSON(http.StatusNotFound, gin.H{"error": "This network admin is not your victim"})
+		return
+	case errors.Is(err, errTransferPending):
+		c.JSON(http.StatusConflict, gin.H{"error": "This victim already has a pending transfer"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create victim transfer"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"transfer": transfer})
+}
+
+func GetMyVictimTransfers(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	query := config.DB.Where("from_demon_id = ? OR to_demon_id = ?", user.ID, user.ID)
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var transfers []models.VictimTransfer
+	if err := query.Preload("Victim").Preload("FromDemon").Preload("ToDemon").Order("created_at DESC").Find(&transfers).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch victim transfers"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"transfers": transfers})
+}
+
+// respondVictimTransfer locks a pending transfer the current user is part of and lets apply resolve it.
+// party selects which side of the transfer may act: "to_demon_id" or "from_demon_id".
+func respondVictimTransfer(c *gin.Context, party string, apply func(tx *gorm.DB, transfer *models.VictimTransfer) error) {
+	transferID := c.Param("id")
+	id, err := strconv.ParseUint(transferID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transfer ID"})
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+
+	var transfer models.VictimTransfer
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where(party+" = ?", user.ID).First(&transfer, id).Error; err != nil {
+			return err
+		}
+		if transfer.Status != models.VictimTransferPending {
+			return errVictimTransferResolved
+		}
+
+		if err := apply(tx, &transfer); err != nil {
+			return err
+		}
+		now := time.Now()
+		transfer.RespondedAt = &now
+		return tx.Omit(clause.Associations).Save(&transfer).Error
+	})
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Victim transfer not found"})
+	case errors.Is(err, errVictimTransferResolved):
+		c.JSON(http.StatusConflict, gin.H{"error": "Victim transfer has already been resolved", "status": transfer.Status})
+	case errors.Is(err, errVictimNotFound), errors.Is(err, errAlreadyAssigned), errors.Is(err, errVictimAtCapacity):
+		respondAssignError(c, err)
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update victim transfer"})
+	default:
+		c.JSON(http.StatusOK, gin.H{"transfer": transfer})
+	}
+}
+
+// transferredDueAt is the deadline the recipient of a victim inherits. A deadline that has already
+// passed was the sender's to miss, so the recipient starts without one.
+func transferredDueAt(dueAt *time.Time, now time.Time) *time.Time {
+	if dueAt == nil || !dueAt.After(now) {
+		return nil
+	}
+	return dueAt
+}
+
+// AcceptVictimTransfer moves the victim to the recipient. The sender's assignment is abandoned and the
+// recipient gets a new one that keeps its lifecycle status; reports stay with the demon who wrote them.
+// If the sender released the victim in the meantime the transfer is cancelled instead.
+func AcceptVictimTransfer(c *gin.Context) {
+	respondVictimTransfer(c, "to_demon_id", func(tx *gorm.DB, transfer *models.VictimTransfer) error {
+		var from models.DemonVictim
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&from, transfer.AssignmentID).Error; err != nil {
+			return err
+		}
+		if from.Status == models.AssignmentStatusAbandoned {
+			transfer.Status = models.VictimTransferCancelled
+			return nil
+		}
+
+		status, dueAt := from.Status, transferredDueAt(from.DueAt, time.Now())
+		note := fmt.Sprintf("Transfer #%d", transfer.ID)
+		if err := releaseAssignment(tx, &from, models.OwnershipTransferredOut, transfer.ToDemonID, &transfer.ToDemonID, note); err != nil {
+			return err
+		}
+
+		to, err := assignVictim(tx, transfer.ToDemonID, transfer.VictimID)
+		if err != nil {
+			return err
+		}
+		updates := map[string]interface{}{"due_at": dueAt}
+		if status == models.AssignmentStatusCompromised {
+			updates["status"] = status
+		}
+		if err := tx.Model(&models.DemonVictim{}).Where("id = ?", to.ID).Updates(updates).Error; err != nil {
+			return err
+		}
+		if err := recordOwnership(tx, to, models.OwnershipTransferredIn, transfer.ToDemonID, &transfer.FromDemonID, note); err != nil {
+			return err
+		}
+
+		transfer.Status = models.VictimTransferAccepted
+		message := fmt.Sprintf("Your transfer of victim %s was accepted", to.Victim.Username)
+		return notifyVictim(tx, transfer.FromDemonID, message, &from.ID)
+	})
+}
+
+func DeclineVictimTransfer(c *gin.Context) {
+	respondVictimTransfer(c, "to_demon_id", func(tx *gorm.DB, transfer *models.VictimTransfer) error {
+		transfer.Status = models.VictimTransferDeclined
+		message := fmt.Sprintf("Your victim transfer #%d was declined", transfer.ID)
+		return notifyVictim(tx, transfer.FromDemonID, message, &transfer.AssignmentID)
+	})
+}
+
+func CancelVictimTransfer(c *gin.Context) {
+	respondVictimTransfer(c, "from_demon_id", func(tx *gorm.DB, transfer *models.VictimTransfer) error {
+		transfer.Status = models.VictimTransferCancelled
+		return nil
+	})
+}
+
+// disputeClaimants loads the active assignments of the disputed victim.
+func disputeClaimants(db *gorm.DB, victimID uint) ([]models.DemonVictim, error) {
+	var claimants []models.DemonVictim
+	err := activeAssignments(db).Where("victim_id = ?", victimID).Preload("Demon").Order("created_at ASC").Find(&claimants).Error
+	return claimants, err
+}
+
+// OpenVictimDispute lets a demon targeting a contested victim ask andrei to decide who keeps them.
+func OpenVictimDispute(c *gin.Context) {
+	var input models.VictimDisputeCreate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	assignment, ok := myAssignment(c)
+	if !ok {
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+
+	dispute := models.VictimDispute{
+		VictimID:   assignment.VictimID,
+		OpenedByID: user.ID,
+		Reason:     input.Reason,
+		Status:     models.DisputeStatusOpen,
+	}
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		// The victim row lock serializes disputes on the same network admin
+		var victim models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&victim, assignment.VictimID).Error; err != nil {
+			return err
+		}
+
+		var open int64
+		if err := tx.Model(&models.VictimDispute{}).Where("victim_id = ? AND status = ?", assignment.VictimID, models.DisputeStatusOpen).
+			Count(&open).Error; err != nil {
+			return err
+		}
+		if open > 0 {
+			return errDisputeOpen
+		}
+
+		claimants, err := disputeClaimants(tx, assignment.VictimID)
+		if err != nil {
+			return err
+		}
+		if len(claimants) < 2 {
+			return errNotContested
+		}
+		dispute.Claimants = claimants
+		return tx.Create(&dispute).Error
+	})
+	switch {
+	case errors.Is(err, errDisputeOpen):
+		c.JSON(http.StatusConflict, gin.H{"error": "This victim already has an open dispute"})
+		return
+	case errors.Is(err, errNotContested):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "No other demon is targeting this victim"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open dispute"})
+		return
+	}
+
+	for _, claimant := range dispute.Claimants {
+		if claimant.DemonID == user.ID {
+			continue
+		}
+		message := fmt.Sprintf("%s opened a dispute over a victim you are targeting", user.Username)
+		if err := notifyVictim(config.DB, claimant.DemonID, message, &claimant.ID); err != nil {
+			log.Printf("Failed to notify demon %d: %v", claimant.DemonID, err)
+		}
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"dispute": dispute})
+}
+
+// GetDisputes lists victim disputes with the demons currently targeting each victim.
+func GetDisputes(c *gin.Context) {
+	query := config.DB.Preload("Victim").Preload("OpenedBy").Order("created_at DESC")
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var disputes []models.VictimDispute
+	if err := query.Find(&disputes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch disputes"})
+		return
+	}
+
+	for i := range disputes {
+		if disputes[i].Status != models.DisputeStatusOpen {
+			continue
+		}
+		claimants, err := disputeClaimants(config.DB, disputes[i].VictimID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch disputes"})
+			return
+		}
+		disputes[i].Claimants = claimants
+	}
+
+	c.JSON(http.StatusOK, gin.H{"disputes": disputes})
+}
+
+// ResolveDispute keeps the winner's assignment and abandons every other active assignment on the victim.
+func ResolveDispute(c *gin.Context) {
+	disputeID := c.Param("id")
+	id, err := strconv.ParseUint(disputeID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dispute ID"})
+		return
+	}
+
+	var input models.VictimDisputeResolve
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+
+	var dispute models.VictimDispute
+	var losers []models.DemonVictim
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&dispute, id).Error; err != nil {
+			return err
+		}
+		if dispute.Status != models.DisputeStatusOpen {
+			return errDisputeResolved
+		}
+
+		var victim models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&victim, dispute.VictimID).Error; err != nil {
+			return err
+		}
+
+		claimants, err := disputeClaimants(tx, dispute.VictimID)
+		if err != nil {
+			return err
+		}
+		var winner *models.DemonVictim
+		for i := range claimants {
+			if claimants[i].DemonID == input.WinnerID {
+				winner = &claimants[i]
+			} else {
+				losers = append(losers, claimants[i])
+			}
+		}
+		if winner == nil {
+			return errNotClaimant
+		}
+
+		note := fmt.Sprintf("Dispute #%d", dispute.ID)
+		if input.Note != "" {
+			note += ": " + input.Note
+		}
+		for i := range losers {
+			if err := releaseAssignment(tx, &losers[i], models.OwnershipDisputeLost, user.ID, &winner.DemonID, note); err != nil {
+				return err
+			}
+			message := fmt.Sprintf("Andrei gave a victim you were targeting to %s", winner.Demon.Username)
+			if err := notifyVictim(tx, losers[i].DemonID, message, &losers[i].ID); err != nil {
+				return err
+			}
+		}
+		if err := recordOwnership(tx, *winner, models.OwnershipDisputeWon, user.ID, nil, note); err != nil {
+			return err
+		}
+		if err := notifyVictim(tx, winner.DemonID, "Andrei resolved a victim dispute in your favour", &winner.ID); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		dispute.Status = models.DisputeStatusResolved
+		dispute.WinnerID = &winner.DemonID
+		dispute.Resolution = input.Note
+		dispute.ResolvedByID = &user.ID
+		dispute.ResolvedAt = &now
+		dispute.Claimants = claimants
+		return tx.Omit(clause.Associations).Save(&dispute).Error
+	})
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Dispute not found"})
+	case errors.Is(err, errDisputeResolved):
+		c.JSON(http.StatusConflict, gin.H{"error": "Dispute has already been resolved"})
+	case errors.Is(err, errNotClaimant):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "The winner must be a demon currently targeting this victim"})
+	case errors.Is(err, errAssignmentChanged):
+		c.JSON(http.StatusConflict, gin.H{"error": "An assignment was changed by someone else, reload and retry"})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve dispute"})
+	default:
+		c.JSON(http.StatusOK, gin.H{"dispute": dispute, "released": len(losers)})
+	}
+}
+
+// GetVictimOwnership returns the ownership history of a network admin, oldest first.
+func GetVictimOwnership(c *gin.Context) {
+	victimID := c.Param("id")
+	id, err := strconv.ParseUint(victimID, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid victim ID"})
+		return
+	}
+
+	history, err := ownershipHistory(config.DB, uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch ownership history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"victim_id": id, "history": history})
+}
+
+func ownershipHistory(db *gorm.DB, victimID uint) ([]models.VictimOwnershipEvent, error) {
+	var history []models.VictimOwnershipEvent
+	err := db.Where("victim_id = ?", victimID).Preload("Demon").Order("created_at ASC, id ASC").Find(&history).Error
+	return history, err
+}
@@ -0,0 +1,25 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTransferredDueAt(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(24 * time.Hour)
+
+	if got := transferredDueAt(&past, now); got != nil {
+		t.Errorf("an overdue deadline was handed to the recipient: %v", got)
+	}
+	if got := transferredDueAt(&now, now); got != nil {
+		t.Errorf("a deadline due now was handed to the recipient: %v", got)
+	}
+	if got := transferredDueAt(&future, now); got == nil || !got.Equal(future) {
+		t.Errorf("transferredDueAt(future) = %v, want %v", got, future)
+	}
+	if got := transferredDueAt(nil, now); got != nil {
+		t.Errorf("transferredDueAt(nil) = %v", got)
+	}
+}
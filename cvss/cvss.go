@@ -0,0 +1,152 @@
+// Package cvss parses CVSS v3.1 vector strings and computes their base score and severity
+// as defined by the FIRST CVSS v3.1 specification.
+package cvss
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const prefix = "CVSS:3.1/"
+
+// Severity ratings of the qualitative severity scale.
+const (
+	SeverityNone     = "none"
+	SeverityLow      = "low"
+	SeverityMedium   = "medium"
+	SeverityHigh     = "high"
+	SeverityCritical = "critical"
+)
+
+// Severities lists the ratings from least to most severe.
+var Severities = []string{SeverityNone, SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical}
+
+// baseMetrics are the mandatory metrics, in the order of the specification.
+var baseMetrics = []string{"AV", "AC", "PR", "UI", "S", "C", "I", "A"}
+
+// metricValues lists the values each metric accepts. Temporal and environmental metrics are
+// validated but do not change the base score.
+var metricValues = map[string]string{
+	"AV": "NALP", "AC": "LH", "PR": "NLH", "UI": "NR", "S": "UC", "C": "HLN", "I": "HLN", "A": "HLN",
+	"E": "XUPFH", "RL": "XOTWU", "RC": "XURC",
+	"CR": "XLMH", "IR": "XLMH", "AR": "XLMH",
+	"MAV": "XNALP", "MAC": "XLH", "MPR": "XNLH", "MUI": "XNR", "MS": "XUC", "MC": "XNLH", "MI": "XNLH", "MA": "XNLH",
+}
+
+var weights = map[string]map[string]float64{
+	"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+	"AC": {"L": 0.77, "H": 0.44},
+	"UI": {"N": 0.85, "R": 0.62},
+	"C":  {"H": 0.56, "L": 0.22, "N": 0},
+	"I":  {"H": 0.56, "L": 0.22, "N": 0},
+	"A":  {"H": 0.56, "L": 0.22, "N": 0},
+}
+
+// Vector is a parsed CVSS v3.1 vector.
+type Vector struct {
+	metrics map[string]string
+}
+
+// Parse validates a vector such as "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H".
+// Every base metric must be present exactly once; metrics may appear in any order.
+func Parse(vector string) (Vector, error) {
+	if !strings.HasPrefix(vector, prefix) {
+		return Vector{}, fmt.Errorf("vector must start with %q", prefix)
+	}
+
+	metrics := make(map[string]string)
+	for _, part := range strings.Split(strings.TrimPrefix(vector, prefix), "/") {
+		name, value, found := strings.Cut(part, ":")
+		if !found || name == "" || value == "" {
+			return Vector{}, fmt.Errorf("malformed metric %q", part)
+		}
+		allowed, known := metricValues[name]
+		if !known {
+			return Vector{}, fmt.Errorf("unknown metric %q", name)
+		}
+		if len(value) != 1 || !strings.Contains(allowed, value) {
+			return Vector{}, fmt.Errorf("invalid value %q for metric %s", value, name)
+		}
+		if _, duplicate := metrics[name]; duplicate {
+			return Vector{}, fmt.Errorf("metric %s is repeated", name)
+		}
+		metrics[name] = value
+	}
+
+	for _, name := range baseMetrics {
+		if _, ok := metrics[name]; !ok {
+			return Vector{}, fmt.Errorf("missing base metric %s", name)
+		}
+	}
+	return Vector{metrics: metrics}, nil
+}
+
+// String returns the vector with its base metrics in canonical order, followed by the other metrics
+// in the order of the specification.
+func (v Vector) String() string {
+	parts := []string{}
+	for _, name := range append(append([]string{}, baseMetrics...),
+		"E", "RL", "RC", "CR", "IR", "AR", "MAV", "MAC", "MPR", "MUI", "MS", "MC", "MI", "MA") {
+		if value, ok := v.metrics[name]; ok {
+			parts = append(parts, name+":"+value)
+		}
+	}
+	return prefix + strings.Join(parts, "/")
+}
+
+// BaseScore computes the base score, from 0.0 to 10.0.
+func (v Vector) BaseScore() float64 {
+	changed := v.metrics["S"] == "C"
+
+	privileges := map[string]float64{"N": 0.85, "L": 0.62, "H": 0.27}
+	if changed {
+		privileges["L"], privileges["H"] = 0.68, 0.5
+	}
+
+	iss := 1 - (1-weights["C"][v.metrics["C"]])*(1-weights["I"][v.metrics["I"]])*(1-weights["A"][v.metrics["A"]])
+	impact := 6.42 * iss
+	if changed {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	if impact <= 0 {
+		return 0
+	}
+
+	exploitability := 8.22 * weights["AV"][v.metrics["AV"]] * weights["AC"][v.metrics["AC"]] *
+		privileges[v.metrics["PR"]] * weights["UI"][v.metrics["UI"]]
+	if changed {
+		return roundUp(math.Min(1.08*(impact+exploitability), 10))
+	}
+	return roundUp(math.Min(impact+exploitability, 10))
+}
+
+// Severity returns the qualitative rating of the base score.
+func (v Vector) Severity() string {
+	return SeverityOf(v.BaseScore())
+}
+
+// SeverityOf maps a score to its qualitative rating.
+func SeverityOf(score float64) string {
+	switch {
+	case score == 0:
+		return SeverityNone
+	case score < 4:
+		return SeverityLow
+	case score < 7:
+		return SeverityMedium
+	case score < 9:
+		return SeverityHigh
+	}
+	return SeverityCritical
+}
+
+// roundUp returns the smallest number with one decimal equal to or higher than value, working on
+// integers to avoid floating point errors as Appendix A of the specification recommends.
+func roundUp(value float64) float64 {
+	scaled := int64(math.Round(value * 100000))
+	if scaled%10000 == 0 {
+		return float64(scaled) / 100000
+	}
+	return float64(scaled/10000+1) / 10
+}
@@ -0,0 +1,87 @@
+package cvss
+
+import "testing"
+
+func TestBaseScore(t *testing.T) {
+	tests := []struct {
+		vector   string
+		score    float64
+		severity string
+	}{
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", 9.8, SeverityCritical},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:N/A:N", 7.5, SeverityHigh},
+		{"CVSS:3.1/AV:L/AC:L/PR:L/UI:N/S:U/C:H/I:H/A:H", 7.8, SeverityHigh},
+		{"CVSS:3.1/AV:P/AC:H/PR:H/UI:R/S:U/C:L/I:N/A:N", 1.6, SeverityLow},
+		// Scope changed
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H", 10.0, SeverityCritical},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N", 6.1, SeverityMedium},
+		{"CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:C/C:L/I:L/A:N", 6.4, SeverityMedium},
+		// No impact scores zero whatever the exploitability
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N", 0, SeverityNone},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:N/I:N/A:N", 0, SeverityNone},
+		// Temporal and environmental metrics do not change the base score
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/E:U/RL:O/RC:R/MAV:P", 9.8, SeverityCritical},
+	}
+	for _, test := range tests {
+		vector, err := Parse(test.vector)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", test.vector, err)
+			continue
+		}
+		if score := vector.BaseScore(); score != test.score {
+			t.Errorf("%s: base score %.1f, want %.1f", test.vector, score, test.score)
+		}
+		if severity := vector.Severity(); severity != test.severity {
+			t.Errorf("%s: severity %q, want %q", test.vector, severity, test.severity)
+		}
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	for _, vector := range []string{
+		"",
+		"AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+		"CVSS:3.0/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H",
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:X",
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/A:H",
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/ZZ:N",
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/",
+		"CVSS:3.1/AV:NN/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+	} {
+		if _, err := Parse(vector); err == nil {
+			t.Errorf("Parse(%q) accepted an invalid vector", vector)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	vector, err := Parse("CVSS:3.1/A:H/E:P/I:H/C:H/S:U/UI:N/PR:N/AC:L/AV:N")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := vector.String(), "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/E:P"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestSeverityOf(t *testing.T) {
+	tests := map[float64]string{
+		0: SeverityNone, 0.1: SeverityLow, 3.9: SeverityLow, 4.0: SeverityMedium, 6.9: SeverityMedium,
+		7.0: SeverityHigh, 8.9: SeverityHigh, 9.0: SeverityCritical, 10.0: SeverityCritical,
+	}
+	for score, want := range tests {
+		if got := SeverityOf(score); got != want {
+			t.Errorf("SeverityOf(%.1f) = %q, want %q", score, got, want)
+		}
+	}
+}
+
+func TestRoundUp(t *testing.T) {
+	tests := map[float64]float64{4.0: 4.0, 4.000002: 4.0, 4.02: 4.1, 4.1: 4.1, 9.99: 10.0}
+	for value, want := range tests {
+		if got := roundUp(value); got != want {
+			t.Errorf("roundUp(%v) = %v, want %v", value, got, want)
+		}
+	}
+}
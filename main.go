@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"andrei-api/config"
+	"andrei-api/controllers"
 	"andrei-api/routes"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -19,14 +20,22 @@ func main() {
 	// Connect to database
 	config.ConnectDatabase()
 
+	// Set up attachment storage
+	config.ConnectStorage()
+
+	// Start background jobs
+	controllers.StartRankingSnapshots()
+	controllers.StartOverdueChecks()
+
 	// Create Gin router
 	r := gin.Default()
 
 	// Add CORS middleware
 	r.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match")
+		c.Header("Access-Control-Expose-Headers", "Content-Disposition, X-Content-SHA256, ETag")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
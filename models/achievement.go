@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// BadgeMetric names the DemonStats counter a badge criterion is evaluated against.
+type BadgeMetric string
+
+const (
+	BadgeMetricReports     BadgeMetric = "reports_count"
+	BadgeMetricVictims     BadgeMetric = "victims_count"
+	BadgeMetricRewards     BadgeMetric = "rewards_count"
+	BadgeMetricPunishments BadgeMetric = "punishments_count"
+	BadgeMetricPoints      BadgeMetric = "total_points"
+)
+
+type Badge struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	Name        string         `json:"name" gorm:"not null;index:idx_badge_name,unique,where:deleted_at IS NULL"`
+	Description string         `json:"description" gorm:"not null"`
+	Metric      BadgeMetric    `json:"metric" gorm:"not null"`
+	Threshold   int64          `json:"threshold" gorm:"not null"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+type DemonBadge struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	DemonID   uint      `json:"demon_id" gorm:"not null;uniqueIndex:idx_demon_badge"`
+	BadgeID   uint      `json:"badge_id" gorm:"not null;uniqueIndex:idx_demon_badge"`
+	Badge     Badge     `json:"badge" gorm:"foreignKey:BadgeID"`
+	AwardedAt time.Time `json:"awarded_at" gorm:"not null"`
+}
+
+type BadgeCreate struct {
+	Name        string      `json:"name" binding:"required"`
+	Description string      `json:"description" binding:"required"`
+	Metric      BadgeMetric `json:"metric" binding:"required"`
+	Threshold   int64       `json:"threshold" binding:"min=0"`
+}
+
+type Level struct {
+	Number    int    `json:"number"`
+	Name      string `json:"name"`
+	MinPoints int64  `json:"min_points"`
+}
+
+// Levels are ordered by MinPoints; a demon holds the highest level whose threshold it has reached.
+var Levels = []Level{
+	{Number: 1, Name: "Imp", MinPoints: 0},
+	{Number: 2, Name: "Fiend", MinPoints: 100},
+	{Number: 3, Name: "Hellhound", MinPoints: 300},
+	{Number: 4, Name: "Archfiend", MinPoints: 700},
+	{Number: 5, Name: "Demon Lord", MinPoints: 1500},
+	{Number: 6, Name: "Right Hand of Andrei", MinPoints: 3000},
+}
@@ -0,0 +1,22 @@
+package models
+
+// AllocationCandidate is a demon considered by the automatic victim allocation.
+type AllocationCandidate struct {
+	DemonID     uint    `json:"demon_id"`
+	Username    string  `json:"username"`
+	Assignments int64   `json:"assignments"`
+	OpenReports int64   `json:"open_reports"`
+	Rank        int64   `json:"rank"`
+	Performance float64 `json:"performance"`
+	Allocated   []uint  `json:"allocated"`
+}
+
+type AllocationPair struct {
+	DemonID  uint `json:"demon_id" binding:"required"`
+	VictimID uint `json:"victim_id" binding:"required"`
+}
+
+// AllocationCommit applies the assignments returned by the allocation preview.
+type AllocationCommit struct {
+	Assignments []AllocationPair `json:"assignments" binding:"required,min=1,dive"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AppealStatus string
+
+const (
+	AppealStatusPending  AppealStatus = "pending"
+	AppealStatusAccepted AppealStatus = "accepted"
+	AppealStatusRejected AppealStatus = "rejected"
+)
+
+type Appeal struct {
+	ID            uint           `json:"id" gorm:"primaryKey"`
+	RewardID      uint           `json:"reward_id" gorm:"not null;index;index:idx_appeal_open,unique,where:status <> 'rejected' AND deleted_at IS NULL"`
+	Reward        Reward         `json:"reward" gorm:"foreignKey:RewardID"`
+	DemonID       uint           `json:"demon_id" gorm:"not null;index"`
+	Demon         User           `json:"demon" gorm:"foreignKey:DemonID"`
+	Justification string         `json:"justification" gorm:"not null"`
+	Status        AppealStatus   `json:"status" gorm:"not null;default:'pending';index"`
+	Reason        string         `json:"reason,omitempty"`
+	ResolvedByID  *uint          `json:"resolved_by_id,omitempty"`
+	ResolvedAt    *time.Time     `json:"resolved_at,omitempty"`
+	ReversalID    *uint          `json:"reversal_id,omitempty"`
+	Reversal      *Reward        `json:"reversal,omitempty" gorm:"foreignKey:ReversalID"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+type AppealCreate struct {
+	RewardID      uint   `json:"reward_id" binding:"required"`
+	Justification string `json:"justification" binding:"required"`
+}
+
+type AppealResolve struct {
+	Reason string `json:"reason"`
+}
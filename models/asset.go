@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Asset is a host in a victim's infrastructure. Address is a canonical IP address or CIDR block.
+type Asset struct {
+	ID             uint           `json:"id" gorm:"primaryKey"`
+	VictimID       uint           `json:"victim_id" gorm:"not null;index"`
+	Victim         User           `json:"victim" gorm:"foreignKey:VictimID"`
+	DiscoveredByID uint           `json:"discovered_by_id" gorm:"not null"`
+	Hostname       string         `json:"hostname,omitempty" gorm:"index"`
+	Address        string         `json:"address,omitempty" gorm:"index"`
+	OS             string         `json:"os,omitempty"`
+	Notes          string         `json:"notes,omitempty"`
+	Services       []AssetService `json:"services" gorm:"constraint:OnDelete:CASCADE"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// AssetService is a port listening on an asset.
+type AssetService struct {
+	ID       uint   `json:"id" gorm:"primaryKey"`
+	AssetID  uint   `json:"asset_id" gorm:"not null;uniqueIndex:idx_asset_service"`
+	Port     int    `json:"port" gorm:"not null;uniqueIndex:idx_asset_service;index"`
+	Protocol string `json:"protocol" gorm:"not null;uniqueIndex:idx_asset_service"`
+	Service  string `json:"service,omitempty"`
+	Version  string `json:"version,omitempty"`
+}
+
+// ReportAsset links a report to the assets it is about.
+type ReportAsset struct {
+	ReportID  uint `gorm:"primaryKey"`
+	AssetID   uint `gorm:"primaryKey;index"`
+	CreatedAt time.Time
+}
+
+type AssetServiceInput struct {
+	Port     int    `json:"port" binding:"required"`
+	Protocol string `json:"protocol"`
+	Service  string `json:"service"`
+	Version  string `json:"version"`
+}
+
+// AssetInput creates or replaces an asset. At least a hostname or an address is required.
+type AssetInput struct {
+	Hostname string              `json:"hostname"`
+	Address  string              `json:"address"`
+	OS       string              `json:"os"`
+	Notes    string              `json:"notes"`
+	Services []AssetServiceInput `json:"services" binding:"dive"`
+}
+
+type ReportAssetsUpdate struct {
+	AssetIDs []uint `json:"asset_ids" binding:"required"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Attachment struct {
+	ID           uint           `json:"id" gorm:"primaryKey"`
+	ReportID     uint           `json:"report_id" gorm:"not null;index"`
+	UploadedByID uint           `json:"uploaded_by_id" gorm:"not null"`
+	FileName     string         `json:"file_name" gorm:"not null"`
+	ContentType  string         `json:"content_type" gorm:"not null"`
+	Size         int64          `json:"size" gorm:"not null"`
+	SHA256       string         `json:"sha256" gorm:"column:sha256;size:64;not null"`
+	StorageKey   string         `json:"-" gorm:"not null;unique"`
+	CreatedAt    time.Time      `json:"created_at"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+type CustodyAction string
+
+const (
+	CustodyActionUpload   CustodyAction = "upload"
+	CustodyActionDownload CustodyAction = "download"
+	// CustodyActionList and CustodyActionView record metadata reads: listing a report's attachments
+	// and looking at one attachment's details
+	CustodyActionList CustodyAction = "list"
+	CustodyActionView CustodyAction = "view"
+)
+
+// CustodyEntry is one link in an attachment's chain of custody. SHA256 is the hash
+// observed while the content was handled (empty for metadata reads); Verified tells whether it
+// matched the one recorded at upload.
+type CustodyEntry struct {
+	ID           uint          `json:"id" gorm:"primaryKey"`
+	AttachmentID uint          `json:"attachment_id" gorm:"not null;index"`
+	UserID       uint          `json:"user_id" gorm:"not null"`
+	User         User          `json:"user" gorm:"foreignKey:UserID"`
+	Action       CustodyAction `json:"action" gorm:"not null"`
+	SHA256       string        `json:"sha256" gorm:"column:sha256;size:64"`
+	Verified     bool          `json:"verified"`
+	IPAddress    string        `json:"ip_address"`
+	UserAgent    string        `json:"user_agent"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ReportComment struct {
+	ID        uint            `json:"id" gorm:"primaryKey"`
+	ReportID  uint            `json:"report_id" gorm:"not null;index"`
+	AuthorID  uint            `json:"author_id" gorm:"not null"`
+	Author    User            `json:"author" gorm:"foreignKey:AuthorID"`
+	ParentID  *uint           `json:"parent_id,omitempty" gorm:"index"`
+	Body      string          `json:"body" gorm:"not null"`
+	EditedAt  *time.Time      `json:"edited_at,omitempty"`
+	Replies   []ReportComment `json:"replies,omitempty" gorm:"-"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	DeletedAt gorm.DeletedAt  `json:"-" gorm:"index"`
+}
+
+type CommentCreate struct {
+	Body     string `json:"body" binding:"required"`
+	ParentID *uint  `json:"parent_id"`
+}
+
+type CommentUpdate struct {
+	Body string `json:"body" binding:"required"`
+}
+
+// ReportRead tracks when a user last read the comments of a report, to compute unread counts.
+type ReportRead struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	ReportID   uint      `json:"report_id" gorm:"not null;uniqueIndex:idx_report_reader"`
+	UserID     uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_report_reader"`
+	LastReadAt time.Time `json:"last_read_at" gorm:"not null"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// DeadlineUpdate sets or, with a null due_at, clears a deadline.
+type DeadlineUpdate struct {
+	DueAt *time.Time `json:"due_at"`
+}
+
+// OverdueWork groups a demon's overdue reports and victim assignments.
+type OverdueWork struct {
+	DemonID     uint          `json:"demon_id"`
+	Username    string        `json:"username"`
+	Reports     []Report      `json:"reports"`
+	Assignments []DemonVictim `json:"assignments"`
+}
+
+// MissedDeadline records each deadline a demon missed. Giving a report or assignment a new due date
+// starts over, so missing it again is a new miss with its own punishment; earlier misses keep theirs.
+type MissedDeadline struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	DemonID      uint      `json:"demon_id" gorm:"not null;index"`
+	ReportID     *uint     `json:"report_id,omitempty" gorm:"index"`
+	AssignmentID *uint     `json:"assignment_id,omitempty" gorm:"index"`
+	DueAt        time.Time `json:"due_at" gorm:"not null"`
+	RewardID     *uint     `json:"reward_id,omitempty"`
+	Reward       *Reward   `json:"reward,omitempty" gorm:"foreignKey:RewardID"`
+	CreatedAt    time.Time `json:"created_at"`
+}
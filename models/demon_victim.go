@@ -5,17 +5,37 @@ import (
 	"gorm.io/gorm"
 )
 
+// AssignmentStatus is the lifecycle of a demon's assignment on a victim.
+type AssignmentStatus string
+
+const (
+	AssignmentStatusTargeted    AssignmentStatus = "targeted"
+	AssignmentStatusCompromised AssignmentStatus = "compromised"
+	// AssignmentStatusAbandoned releases the victim; reports can no longer be filed on the assignment
+	AssignmentStatusAbandoned AssignmentStatus = "abandoned"
+)
+
+// DemonVictim assigns a victim to a demon. A demon has at most one active (not abandoned) assignment per victim.
 type DemonVictim struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	DemonID   uint           `json:"demon_id" gorm:"not null"`
-	Demon     User           `json:"demon" gorm:"foreignKey:DemonID"`
-	VictimID  uint           `json:"victim_id" gorm:"not null"`
-	Victim    User           `json:"victim" gorm:"foreignKey:VictimID"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	ID              uint             `json:"id" gorm:"primaryKey"`
+	DemonID         uint             `json:"demon_id" gorm:"not null;index:idx_demon_victim_active,unique,where:status <> 'abandoned' AND deleted_at IS NULL"`
+	Demon           User             `json:"demon" gorm:"foreignKey:DemonID"`
+	VictimID        uint             `json:"victim_id" gorm:"not null;index:idx_demon_victim_active,unique;index"`
+	Victim          User             `json:"victim" gorm:"foreignKey:VictimID"`
+	Status          AssignmentStatus `json:"status" gorm:"not null;default:'targeted'"`
+	ReleasedAt      *time.Time       `json:"released_at,omitempty"`
+	DueAt           *time.Time       `json:"due_at,omitempty"`
+	OverdueAt       *time.Time       `json:"overdue_at,omitempty"`
+	OverdueRewardID *uint            `json:"overdue_reward_id,omitempty"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt   `json:"-" gorm:"index"`
 }
 
 type AssignVictimRequest struct {
 	VictimID uint `json:"victim_id" binding:"required"`
+}
+
+type AssignmentStatusUpdate struct {
+	Status AssignmentStatus `json:"status" binding:"required"`
 }
\ No newline at end of file
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// DossierEvent is one entry of a victim's timeline. Only the IDs relevant to the event are set.
+type DossierEvent struct {
+	At           time.Time `json:"at"`
+	Type         string    `json:"type"`
+	Description  string    `json:"description"`
+	DemonID      *uint     `json:"demon_id,omitempty"`
+	AssignmentID *uint     `json:"assignment_id,omitempty"`
+	ReportID     *uint     `json:"report_id,omitempty"`
+	PostID       *uint     `json:"post_id,omitempty"`
+}
+
+// Exposure rates how compromised a network admin is, from 0 to 100. Factors holds the points each
+// signal contributed before the total was capped.
+type Exposure struct {
+	Score   int            `json:"score"`
+	Level   string         `json:"level"`
+	Factors map[string]int `json:"factors"`
+}
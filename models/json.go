@@ -0,0 +1,39 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// JSONMap is a JSON object stored in a jsonb column.
+type JSONMap map[string]interface{}
+
+func (JSONMap) GormDataType() string {
+	return "jsonb"
+}
+
+func (m JSONMap) Value() (driver.Value, error) {
+	if m == nil {
+		return nil, nil
+	}
+	value, err := json.Marshal(m)
+	return string(value), err
+}
+
+func (m *JSONMap) Scan(value interface{}) error {
+	return scanJSON(value, m)
+}
+
+// scanJSON decodes a json/jsonb column into dest. NULL leaves dest untouched.
+func scanJSON(value interface{}, dest interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, dest)
+	case string:
+		return json.Unmarshal([]byte(v), dest)
+	}
+	return errors.New("unsupported JSON column value")
+}
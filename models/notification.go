@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+type NotificationType string
+
+const (
+	NotificationTypeMention NotificationType = "mention"
+	NotificationTypeOverdue NotificationType = "overdue"
+	NotificationTypeVictim  NotificationType = "victim"
+)
+
+type Notification struct {
+	ID           uint             `json:"id" gorm:"primaryKey"`
+	UserID       uint             `json:"user_id" gorm:"not null;index"`
+	Type         NotificationType `json:"type" gorm:"not null"`
+	Message      string           `json:"message" gorm:"not null"`
+	ReportID     *uint            `json:"report_id,omitempty"`
+	CommentID    *uint            `json:"comment_id,omitempty"`
+	AssignmentID *uint            `json:"assignment_id,omitempty"`
+	ReadAt       *time.Time       `json:"read_at,omitempty"`
+	CreatedAt    time.Time        `json:"created_at"`
+}
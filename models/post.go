@@ -13,6 +13,7 @@ type Post struct {
 	AuthorID  *uint          `json:"author_id,omitempty"`
 	Author    *User          `json:"author,omitempty" gorm:"foreignKey:AuthorID"`
 	Anonymous bool           `json:"anonymous" gorm:"default:false"`
+	Version   uint           `json:"version" gorm:"not null;default:1"`
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
@@ -22,4 +23,11 @@ type PostCreate struct {
 	Title string `json:"title" binding:"required"`
 	Body  string `json:"body" binding:"required"`
 	Media string `json:"media,omitempty"`
+}
+
+// PostUpdate edits a post. Omitted fields are left unchanged.
+type PostUpdate struct {
+	Title *string `json:"title" binding:"omitempty,min=1"`
+	Body  *string `json:"body" binding:"omitempty,min=1"`
+	Media *string `json:"media"`
 }
\ No newline at end of file
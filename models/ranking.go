@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// ScoringFormula holds the weights andrei uses to turn demon stats into a ranking score:
+// score = points*PointsWeight + reports*ReportsWeight + victims*VictimsWeight - punishments*PunishmentsWeight
+type ScoringFormula struct {
+	ID                uint      `json:"-" gorm:"primaryKey"`
+	PointsWeight      float64   `json:"points_weight" gorm:"not null"`
+	ReportsWeight     float64   `json:"reports_weight" gorm:"not null"`
+	VictimsWeight     float64   `json:"victims_weight" gorm:"not null"`
+	PunishmentsWeight float64   `json:"punishments_weight" gorm:"not null"`
+	UpdatedByID       *uint     `json:"updated_by_id,omitempty"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// DefaultScoringFormula ranks demons by total points only.
+var DefaultScoringFormula = ScoringFormula{ID: 1, PointsWeight: 1}
+
+type ScoringFormulaUpdate struct {
+	PointsWeight      *float64 `json:"points_weight"`
+	ReportsWeight     *float64 `json:"reports_weight"`
+	VictimsWeight     *float64 `json:"victims_weight"`
+	PunishmentsWeight *float64 `json:"punishments_weight"`
+}
+
+type RankingEntry struct {
+	Rank             int64   `json:"rank"`
+	DemonID          uint    `json:"demon_id"`
+	Username         string  `json:"username"`
+	Score            float64 `json:"score"`
+	VictimsCount     int64   `json:"victims_count"`
+	RewardsCount     int64   `json:"rewards_count"`
+	PunishmentsCount int64   `json:"punishments_count"`
+	TotalPoints      int64   `json:"total_points"`
+	ReportsCount     int64   `json:"reports_count"`
+}
+
+// RankingSnapshot is a demon's position in the ranking at a point in time.
+// All demons captured in the same run share TakenAt.
+type RankingSnapshot struct {
+	ID               uint      `json:"id" gorm:"primaryKey"`
+	TakenAt          time.Time `json:"taken_at" gorm:"not null;index"`
+	SeasonID         *uint     `json:"season_id,omitempty"`
+	DemonID          uint      `json:"demon_id" gorm:"not null;index"`
+	Rank             int64     `json:"rank" gorm:"not null"`
+	Score            float64   `json:"score"`
+	VictimsCount     int64     `json:"victims_count"`
+	RewardsCount     int64     `json:"rewards_count"`
+	PunishmentsCount int64     `json:"punishments_count"`
+	TotalPoints      int64     `json:"total_points"`
+	ReportsCount     int64     `json:"reports_count"`
+}
+
+type RankMovement struct {
+	DemonID       uint      `json:"demon_id"`
+	Username      string    `json:"username"`
+	FromRank      int64     `json:"from_rank"`
+	ToRank        int64     `json:"to_rank"`
+	RankChange    int64     `json:"rank_change"`
+	PointsChange  int64     `json:"points_change"`
+	ReportsChange int64     `json:"reports_change"`
+	FromTakenAt   time.Time `json:"from_taken_at"`
+	ToTakenAt     time.Time `json:"to_taken_at"`
+}
@@ -5,22 +5,82 @@ import (
 	"gorm.io/gorm"
 )
 
+type ReportStatus string
+
+const (
+	// ReportStatusDraft reports are prepared for the demon (e.g. from a scan import) and not yet submitted
+	ReportStatusDraft      ReportStatus = "draft"
+	ReportStatusPending    ReportStatus = "pending"
+	ReportStatusInProgress ReportStatus = "in_progress"
+	ReportStatusCompleted  ReportStatus = "completed"
+	ReportStatusFailed     ReportStatus = "failed"
+	ReportStatusReviewed   ReportStatus = "reviewed"
+	ReportStatusRejected   ReportStatus = "rejected"
+	// ReportStatusDiscarded drafts were dropped by their demon without being submitted
+	ReportStatusDiscarded ReportStatus = "discarded"
+)
+
 type Report struct {
-	ID          uint           `json:"id" gorm:"primaryKey"`
-	DemonID     uint           `json:"demon_id" gorm:"not null"`
-	Demon       User           `json:"demon" gorm:"foreignKey:DemonID"`
-	VictimID    uint           `json:"victim_id" gorm:"not null"`
-	Victim      User           `json:"victim" gorm:"foreignKey:VictimID"`
-	Title       string         `json:"title" gorm:"not null"`
-	Description string         `json:"description" gorm:"not null"`
-	Status      string         `json:"status" gorm:"default:'pending'"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+	ID              uint              `json:"id" gorm:"primaryKey"`
+	DemonID         uint              `json:"demon_id" gorm:"not null"`
+	Demon           User              `json:"demon" gorm:"foreignKey:DemonID"`
+	VictimID        uint              `json:"victim_id" gorm:"not null"`
+	Victim          User              `json:"victim" gorm:"foreignKey:VictimID"`
+	AssignmentID    *uint             `json:"assignment_id,omitempty" gorm:"index"`
+	Title           string            `json:"title" gorm:"not null"`
+	Description     string            `json:"description" gorm:"not null"`
+	Status          ReportStatus      `json:"status" gorm:"default:'pending'"`
+	Version         uint              `json:"version" gorm:"not null;default:1"`
+	TemplateID      *uint             `json:"template_id,omitempty" gorm:"index"`
+	Data            JSONMap           `json:"data,omitempty"`
+	CVSSVector      string            `json:"cvss_vector,omitempty"`
+	CVSSScore       *float64          `json:"cvss_score,omitempty" gorm:"index"`
+	Severity        string            `json:"severity,omitempty" gorm:"index"`
+	ReviewFeedback  string            `json:"review_feedback,omitempty"`
+	ReviewedByID    *uint             `json:"reviewed_by_id,omitempty"`
+	ReviewedAt      *time.Time        `json:"reviewed_at,omitempty"`
+	ReviewRewardID  *uint             `json:"review_reward_id,omitempty"`
+	DueAt           *time.Time        `json:"due_at,omitempty"`
+	OverdueAt       *time.Time        `json:"overdue_at,omitempty"`
+	OverdueRewardID *uint             `json:"overdue_reward_id,omitempty"`
+	Assets          []Asset           `json:"assets,omitempty" gorm:"many2many:report_assets"`
+	Techniques      []ReportTechnique `json:"techniques,omitempty" gorm:"constraint:OnDelete:CASCADE"`
+	UnreadComments  int64             `json:"unread_comments" gorm:"-"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt    `json:"-" gorm:"index"`
 }
 
 type ReportCreate struct {
-	VictimID    uint   `json:"victim_id" binding:"required"`
-	Title       string `json:"title" binding:"required"`
-	Description string `json:"description" binding:"required"`
-}
\ No newline at end of file
+	VictimID     uint     `json:"victim_id" binding:"required"`
+	Title        string   `json:"title" binding:"required"`
+	Description  string   `json:"description" binding:"required"`
+	TemplateID   *uint    `json:"template_id"`
+	Data         JSONMap  `json:"data"`
+	AssetIDs     []uint   `json:"asset_ids"`
+	CVSSVector   string   `json:"cvss_vector"`
+	TechniqueIDs []string `json:"technique_ids"`
+}
+
+// ReportStatusChange records every status transition of a report.
+type ReportStatusChange struct {
+	ID          uint         `json:"id" gorm:"primaryKey"`
+	ReportID    uint         `json:"report_id" gorm:"not null;index"`
+	FromStatus  ReportStatus `json:"from_status"`
+	ToStatus    ReportStatus `json:"to_status" gorm:"not null"`
+	ChangedByID uint         `json:"changed_by_id" gorm:"not null"`
+	ChangedBy   User         `json:"changed_by" gorm:"foreignKey:ChangedByID"`
+	Note        string       `json:"note,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+type ReportStatusUpdate struct {
+	Status ReportStatus `json:"status" binding:"required"`
+	Note   string       `json:"note"`
+}
+
+type ReportReview struct {
+	Decision string `json:"decision" binding:"required,oneof=approve reject"`
+	Feedback string `json:"feedback"`
+	Points   int    `json:"points" binding:"gte=0"`
+}
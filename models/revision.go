@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// ReportRevision stores the content of a report after each edit. Revision 1 is the content at creation.
+type ReportRevision struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	ReportID    uint      `json:"report_id" gorm:"not null;uniqueIndex:idx_report_revision"`
+	Number      int       `json:"number" gorm:"not null;uniqueIndex:idx_report_revision"`
+	Title       string    `json:"title" gorm:"not null"`
+	Description string    `json:"description" gorm:"not null"`
+	Data        JSONMap   `json:"data,omitempty"`
+	CVSSVector  string    `json:"cvss_vector,omitempty"`
+	EditedByID  uint      `json:"edited_by_id" gorm:"not null"`
+	EditedBy    User      `json:"edited_by" gorm:"foreignKey:EditedByID"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ReportEdit changes the content of a report. Omitted fields are left unchanged; data replaces the whole object
+// and an empty cvss_vector removes the vector.
+type ReportEdit struct {
+	Title       *string `json:"title" binding:"omitempty,min=1"`
+	Description *string `json:"description" binding:"omitempty,min=1"`
+	Data        JSONMap `json:"data"`
+	CVSSVector  *string `json:"cvss_vector"`
+}
+
+// FieldChange is one difference between two revisions. Data fields are named data.<field>.
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
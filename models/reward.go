@@ -10,6 +10,10 @@ type RewardType string
 const (
 	RewardTypeReward     RewardType = "reward"
 	RewardTypePunishment RewardType = "punishment"
+	// RewardTypeReversal cancels the points of a punishment after an accepted appeal
+	RewardTypeReversal RewardType = "reversal"
+	// RewardTypeTransfer moves points between demons (negative for the sender, positive for the recipient)
+	RewardTypeTransfer RewardType = "transfer"
 )
 
 type Reward struct {
@@ -20,11 +24,19 @@ type Reward struct {
 	Title       string         `json:"title" gorm:"not null"`
 	Description string         `json:"description" gorm:"not null"`
 	Points      int            `json:"points" gorm:"default:0"`
+	Version     uint           `json:"version" gorm:"not null;default:1"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
+// RewardUpdate edits a reward or punishment. Omitted fields are left unchanged.
+type RewardUpdate struct {
+	Title       *string `json:"title" binding:"omitempty,min=1"`
+	Description *string `json:"description" binding:"omitempty,min=1"`
+	Points      *int    `json:"points"`
+}
+
 type RewardCreate struct {
 	DemonID     uint       `json:"demon_id" binding:"required"`
 	Type        RewardType `json:"type" binding:"required"`
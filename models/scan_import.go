@@ -0,0 +1,78 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ScanImportStatus string
+
+const (
+	ScanImportPreview   ScanImportStatus = "preview"
+	ScanImportCommitted ScanImportStatus = "committed"
+)
+
+type ScanService struct {
+	Port        int    `json:"port"`
+	Protocol    string `json:"protocol"`
+	Service     string `json:"service,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type ScanHost struct {
+	Address  string        `json:"address,omitempty"`
+	Hostname string        `json:"hostname,omitempty"`
+	OS       string        `json:"os,omitempty"`
+	Services []ScanService `json:"services"`
+}
+
+// ScanHosts are the hosts parsed from a scan, stored as jsonb until the import is committed.
+type ScanHosts []ScanHost
+
+func (ScanHosts) GormDataType() string {
+	return "jsonb"
+}
+
+func (h ScanHosts) Value() (driver.Value, error) {
+	if h == nil {
+		return "[]", nil
+	}
+	encoded, err := json.Marshal(h)
+	return string(encoded), err
+}
+
+func (h *ScanHosts) Scan(value interface{}) error {
+	return scanJSON(value, h)
+}
+
+// ScanImport is an uploaded scanner report of a victim. It is previewed first and applied to the
+// victim's assets and reports when committed.
+type ScanImport struct {
+	ID          uint             `json:"id" gorm:"primaryKey"`
+	VictimID    uint             `json:"victim_id" gorm:"not null;index"`
+	DemonID     uint             `json:"demon_id" gorm:"not null;index"`
+	FileName    string           `json:"file_name"`
+	Scanner     string           `json:"scanner"`
+	ScanArgs    string           `json:"scan_args,omitempty"`
+	ScannedAt   *time.Time       `json:"scanned_at,omitempty"`
+	Hosts       ScanHosts        `json:"hosts"`
+	Status      ScanImportStatus `json:"status" gorm:"not null"`
+	CommittedAt *time.Time       `json:"committed_at,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt   `json:"-" gorm:"index"`
+}
+
+// ImportHostPlan is what committing an import does to one scanned host.
+type ImportHostPlan struct {
+	Address     string         `json:"address,omitempty"`
+	Hostname    string         `json:"hostname,omitempty"`
+	Action      string         `json:"action"`
+	AssetID     *uint          `json:"asset_id,omitempty"`
+	NewServices []AssetService `json:"new_services"`
+	Updated     []AssetService `json:"updated_services"`
+	Drafts      []string       `json:"draft_reports"`
+}
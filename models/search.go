@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// ReportSearchResult is a report matching a full-text search, with its relevance and highlighted snippets.
+type ReportSearchResult struct {
+	ID             uint         `json:"id"`
+	DemonID        uint         `json:"demon_id"`
+	VictimID       uint         `json:"victim_id"`
+	Title          string       `json:"title"`
+	Status         ReportStatus `json:"status"`
+	CreatedAt      time.Time    `json:"created_at"`
+	Rank           float64      `json:"rank"`
+	TitleHighlight string       `json:"title_highlight"`
+	Snippet        string       `json:"snippet"`
+}
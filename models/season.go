@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SeasonStatus string
+
+const (
+	SeasonStatusActive SeasonStatus = "active"
+	SeasonStatusClosed SeasonStatus = "closed"
+)
+
+// Season is a ranking period. At most one season is active at a time (enforced by a unique index).
+type Season struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	Name      string         `json:"name" gorm:"not null"`
+	StartsAt  time.Time      `json:"starts_at" gorm:"not null"`
+	EndsAt    *time.Time     `json:"ends_at,omitempty"`
+	Status    SeasonStatus   `json:"status" gorm:"not null;default:'active';index;index:idx_season_active,unique,where:status = 'active' AND deleted_at IS NULL"`
+	ClosedAt  *time.Time     `json:"closed_at,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// SeasonStanding is the archived position of a demon once a season has been closed.
+type SeasonStanding struct {
+	ID               uint      `json:"id" gorm:"primaryKey"`
+	SeasonID         uint      `json:"season_id" gorm:"not null;uniqueIndex:idx_season_demon"`
+	DemonID          uint      `json:"demon_id" gorm:"not null;uniqueIndex:idx_season_demon"`
+	Demon            User      `json:"demon" gorm:"foreignKey:DemonID"`
+	Rank             int64     `json:"rank" gorm:"not null"`
+	Score            float64   `json:"score"`
+	VictimsCount     int64     `json:"victims_count"`
+	RewardsCount     int64     `json:"rewards_count"`
+	PunishmentsCount int64     `json:"punishments_count"`
+	TotalPoints      int64     `json:"total_points"`
+	ReportsCount     int64     `json:"reports_count"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type SeasonCreate struct {
+	Name     string     `json:"name" binding:"required"`
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+}
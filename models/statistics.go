@@ -7,6 +7,10 @@ type DemonStats struct {
 	PunishmentsCount int64  `json:"punishments_count"`
 	TotalPoints    int64  `json:"total_points"`
 	ReportsCount   int64  `json:"reports_count"`
+	PointsSent     int64  `json:"points_sent"`
+	PointsReceived int64  `json:"points_received"`
+	TransferFees   int64  `json:"transfer_fees"`
+	SeverityCounts map[string]int64 `json:"severity_counts"`
 }
 
 type PlatformStats struct {
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// ReportTechnique tags a report with a technique of the bundled ATT&CK-style taxonomy.
+type ReportTechnique struct {
+	ReportID    uint      `json:"-" gorm:"primaryKey"`
+	TechniqueID string    `json:"technique_id" gorm:"primaryKey;size:16;index"`
+	Name        string    `json:"name" gorm:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type ReportTechniquesUpdate struct {
+	TechniqueIDs []string `json:"technique_ids" binding:"required"`
+}
+
+// TechniqueUsage counts the reports tagged with a technique and the demons who filed them.
+type TechniqueUsage struct {
+	TechniqueID string    `json:"technique_id"`
+	Name        string    `json:"name" gorm:"-"`
+	Tactics     []string  `json:"tactics" gorm:"-"`
+	Reports     int64     `json:"reports"`
+	Demons      int64     `json:"demons"`
+	LastUsedAt  time.Time `json:"last_used_at"`
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TemplateFieldType is the type of value a template field accepts.
+type TemplateFieldType string
+
+const (
+	TemplateFieldString TemplateFieldType = "string"
+	TemplateFieldNumber TemplateFieldType = "number"
+	TemplateFieldEnum   TemplateFieldType = "enum"
+	// TemplateFieldDate accepts YYYY-MM-DD or RFC 3339 dates
+	TemplateFieldDate TemplateFieldType = "date"
+	// TemplateFieldList accepts a list of strings
+	TemplateFieldList TemplateFieldType = "list"
+)
+
+type TemplateField struct {
+	Name     string            `json:"name" binding:"required"`
+	Label    string            `json:"label,omitempty"`
+	Type     TemplateFieldType `json:"type" binding:"required"`
+	Required bool              `json:"required"`
+	Options  []string          `json:"options,omitempty"`
+}
+
+// TemplateSchema describes the structured data of reports using a template, stored as jsonb.
+type TemplateSchema struct {
+	Fields []TemplateField `json:"fields"`
+}
+
+func (TemplateSchema) GormDataType() string {
+	return "jsonb"
+}
+
+func (s TemplateSchema) Value() (driver.Value, error) {
+	value, err := json.Marshal(s)
+	return string(value), err
+}
+
+func (s *TemplateSchema) Scan(value interface{}) error {
+	return scanJSON(value, s)
+}
+
+type ReportTemplate struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	Name        string         `json:"name" gorm:"not null;index:idx_report_template_name,unique,where:deleted_at IS NULL"`
+	Description string         `json:"description"`
+	Schema      TemplateSchema `json:"schema" gorm:"not null"`
+	CreatedByID uint           `json:"created_by_id" gorm:"not null"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+type ReportTemplateCreate struct {
+	Name        string          `json:"name" binding:"required"`
+	Description string          `json:"description"`
+	Fields      []TemplateField `json:"fields" binding:"required,min=1,dive"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TransferStatus string
+
+const (
+	TransferStatusPendingApproval TransferStatus = "pending_approval"
+	TransferStatusCompleted       TransferStatus = "completed"
+	TransferStatusRejected        TransferStatus = "rejected"
+)
+
+type PointTransfer struct {
+	ID           uint           `json:"id" gorm:"primaryKey"`
+	FromDemonID  uint           `json:"from_demon_id" gorm:"not null;index"`
+	FromDemon    User           `json:"from_demon" gorm:"foreignKey:FromDemonID"`
+	ToDemonID    uint           `json:"to_demon_id" gorm:"not null;index"`
+	ToDemon      User           `json:"to_demon" gorm:"foreignKey:ToDemonID"`
+	Amount       int            `json:"amount" gorm:"not null"`
+	Fee          int            `json:"fee" gorm:"not null;default:0"`
+	Note         string         `json:"note"`
+	Status       TransferStatus `json:"status" gorm:"not null;index"`
+	Reason       string         `json:"reason,omitempty"`
+	ReviewedByID *uint          `json:"reviewed_by_id,omitempty"`
+	ReviewedAt   *time.Time     `json:"reviewed_at,omitempty"`
+	DebitID      *uint          `json:"debit_id,omitempty"`
+	CreditID     *uint          `json:"credit_id,omitempty"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+type TransferCreate struct {
+	ToDemonID uint   `json:"to_demon_id" binding:"required"`
+	Amount    int    `json:"amount" binding:"required,gt=0"`
+	Note      string `json:"note"`
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type VictimTransferStatus string
+
+const (
+	VictimTransferPending   VictimTransferStatus = "pending"
+	VictimTransferAccepted  VictimTransferStatus = "accepted"
+	VictimTransferDeclined  VictimTransferStatus = "declined"
+	VictimTransferCancelled VictimTransferStatus = "cancelled"
+)
+
+// VictimTransfer is a demon's offer to hand one of their victims to another demon, who must accept it.
+type VictimTransfer struct {
+	ID           uint                 `json:"id" gorm:"primaryKey"`
+	AssignmentID uint                 `json:"assignment_id" gorm:"not null;index"`
+	VictimID     uint                 `json:"victim_id" gorm:"not null;index"`
+	Victim       User                 `json:"victim" gorm:"foreignKey:VictimID"`
+	FromDemonID  uint                 `json:"from_demon_id" gorm:"not null;index"`
+	FromDemon    User                 `json:"from_demon" gorm:"foreignKey:FromDemonID"`
+	ToDemonID    uint                 `json:"to_demon_id" gorm:"not null;index"`
+	ToDemon      User                 `json:"to_demon" gorm:"foreignKey:ToDemonID"`
+	Note         string               `json:"note,omitempty"`
+	Status       VictimTransferStatus `json:"status" gorm:"not null;index"`
+	RespondedAt  *time.Time           `json:"responded_at,omitempty"`
+	CreatedAt    time.Time            `json:"created_at"`
+	UpdatedAt    time.Time            `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt       `json:"-" gorm:"index"`
+}
+
+type VictimTransferCreate struct {
+	ToDemonID uint   `json:"to_demon_id" binding:"required"`
+	Note      string `json:"note"`
+}
+
+type DisputeStatus string
+
+const (
+	DisputeStatusOpen     DisputeStatus = "open"
+	DisputeStatusResolved DisputeStatus = "resolved"
+)
+
+// VictimDispute asks andrei to decide which of the demons targeting a victim keeps it.
+type VictimDispute struct {
+	ID           uint           `json:"id" gorm:"primaryKey"`
+	VictimID     uint           `json:"victim_id" gorm:"not null;index"`
+	Victim       User           `json:"victim" gorm:"foreignKey:VictimID"`
+	OpenedByID   uint           `json:"opened_by_id" gorm:"not null"`
+	OpenedBy     User           `json:"opened_by" gorm:"foreignKey:OpenedByID"`
+	Reason       string         `json:"reason" gorm:"not null"`
+	Status       DisputeStatus  `json:"status" gorm:"not null;index"`
+	WinnerID     *uint          `json:"winner_id,omitempty"`
+	Resolution   string         `json:"resolution,omitempty"`
+	ResolvedByID *uint          `json:"resolved_by_id,omitempty"`
+	ResolvedAt   *time.Time     `json:"resolved_at,omitempty"`
+	Claimants    []DemonVictim  `json:"claimants,omitempty" gorm:"-"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+type VictimDisputeCreate struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type VictimDisputeResolve struct {
+	WinnerID uint   `json:"winner_id" binding:"required"`
+	Note     string `json:"note"`
+}
+
+type OwnershipEventType string
+
+const (
+	OwnershipAssigned       OwnershipEventType = "assigned"
+	OwnershipCompromised    OwnershipEventType = "compromised"
+	OwnershipReleased       OwnershipEventType = "released"
+	OwnershipTransferredOut OwnershipEventType = "transferred_out"
+	OwnershipTransferredIn  OwnershipEventType = "transferred_in"
+	OwnershipDisputeWon     OwnershipEventType = "dispute_won"
+	OwnershipDisputeLost    OwnershipEventType = "dispute_lost"
+)
+
+// VictimOwnershipEvent records every change of which demons hold a victim.
+type VictimOwnershipEvent struct {
+	ID             uint               `json:"id" gorm:"primaryKey"`
+	VictimID       uint               `json:"victim_id" gorm:"not null;index"`
+	DemonID        uint               `json:"demon_id" gorm:"not null"`
+	Demon          User               `json:"demon" gorm:"foreignKey:DemonID"`
+	Event          OwnershipEventType `json:"event" gorm:"not null"`
+	AssignmentID   uint               `json:"assignment_id" gorm:"not null"`
+	RelatedDemonID *uint              `json:"related_demon_id,omitempty"`
+	ActorID        uint               `json:"actor_id" gorm:"not null"`
+	Note           string             `json:"note,omitempty"`
+	CreatedAt      time.Time          `json:"created_at"`
+}
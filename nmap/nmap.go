@@ -0,0 +1,182 @@
+// Package nmap reads the XML output of Nmap (nmap -oX) and keeps what is needed to inventory
+// hosts: their addresses, hostnames, best OS guess and open ports with the detected services.
+package nmap
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Run is a parsed scan. Only hosts that were up are kept, with their open ports.
+type Run struct {
+	Scanner string
+	Version string
+	Args    string
+	Start   time.Time
+	Hosts   []Host
+}
+
+type Host struct {
+	// Address is the IPv4 or IPv6 address; MAC addresses are ignored
+	Address   string
+	Hostnames []string
+	OS        string
+	Ports     []Port
+}
+
+type Port struct {
+	Protocol  string
+	Port      int
+	Service   string
+	Product   string
+	Version   string
+	ExtraInfo string
+	OSType    string
+}
+
+// Describe joins the detected product, version and extra information, e.g. "OpenSSH 8.9p1 (Ubuntu Linux)".
+func (p Port) Describe() string {
+	parts := []string{}
+	for _, part := range []string{p.Product, p.Version} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	description := strings.Join(parts, " ")
+	if p.ExtraInfo != "" {
+		if description != "" {
+			description += " "
+		}
+		description += "(" + p.ExtraInfo + ")"
+	}
+	return description
+}
+
+type xmlRun struct {
+	XMLName xml.Name  `xml:"nmaprun"`
+	Scanner string    `xml:"scanner,attr"`
+	Version string    `xml:"version,attr"`
+	Args    string    `xml:"args,attr"`
+	Start   string    `xml:"start,attr"`
+	Hosts   []xmlHost `xml:"host"`
+}
+
+type xmlHost struct {
+	Status struct {
+		State string `xml:"state,attr"`
+	} `xml:"status"`
+	Addresses []struct {
+		Addr string `xml:"addr,attr"`
+		Type string `xml:"addrtype,attr"`
+	} `xml:"address"`
+	Hostnames []struct {
+		Name string `xml:"name,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"hostnames>hostname"`
+	Ports []struct {
+		Protocol string `xml:"protocol,attr"`
+		PortID   string `xml:"portid,attr"`
+		State    struct {
+			State string `xml:"state,attr"`
+		} `xml:"state"`
+		Service struct {
+			Name      string `xml:"name,attr"`
+			Product   string `xml:"product,attr"`
+			Version   string `xml:"version,attr"`
+			ExtraInfo string `xml:"extrainfo,attr"`
+			OSType    string `xml:"ostype,attr"`
+		} `xml:"service"`
+	} `xml:"ports>port"`
+	OSMatches []struct {
+		Name     string `xml:"name,attr"`
+		Accuracy string `xml:"accuracy,attr"`
+	} `xml:"os>osmatch"`
+}
+
+// ErrNotNmap is returned when the document is not an Nmap XML report.
+var ErrNotNmap = errors.New("not an nmap XML report")
+
+// Parse reads an Nmap XML report. Go's XML decoder does not fetch external entities or DTDs.
+func Parse(r io.Reader) (*Run, error) {
+	var raw xmlRun
+	decoder := xml.NewDecoder(r)
+	// Nmap declares its output as ISO-8859-1 on some platforms; the attributes we read are ASCII
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) { return input, nil }
+	if err := decoder.Decode(&raw); err != nil {
+		var syntaxErr *xml.SyntaxError
+		var unmarshalErr xml.UnmarshalError
+		if errors.Is(err, io.EOF) || errors.As(err, &syntaxErr) || errors.As(err, &unmarshalErr) {
+			return nil, ErrNotNmap
+		}
+		return nil, err
+	}
+
+	run := &Run{Scanner: raw.Scanner, Version: raw.Version, Args: raw.Args}
+	if start, err := strconv.ParseInt(raw.Start, 10, 64); err == nil {
+		run.Start = time.Unix(start, 0).UTC()
+	}
+
+	for _, rawHost := range raw.Hosts {
+		if rawHost.Status.State != "" && rawHost.Status.State != "up" {
+			continue
+		}
+
+		var host Host
+		for _, address := range rawHost.Addresses {
+			if address.Type == "ipv4" || address.Type == "ipv6" {
+				host.Address = address.Addr
+				break
+			}
+		}
+		// User-supplied names come before reverse DNS
+		sort.SliceStable(rawHost.Hostnames, func(i, j int) bool {
+			return rawHost.Hostnames[i].Type == "user" && rawHost.Hostnames[j].Type != "user"
+		})
+		for _, hostname := range rawHost.Hostnames {
+			if hostname.Name != "" {
+				host.Hostnames = append(host.Hostnames, hostname.Name)
+			}
+		}
+
+		bestAccuracy := -1
+		for _, match := range rawHost.OSMatches {
+			accuracy, _ := strconv.Atoi(match.Accuracy)
+			if accuracy > bestAccuracy {
+				host.OS, bestAccuracy = match.Name, accuracy
+			}
+		}
+
+		for _, rawPort := range rawHost.Ports {
+			if rawPort.State.State != "open" {
+				continue
+			}
+			number, err := strconv.Atoi(rawPort.PortID)
+			if err != nil {
+				continue
+			}
+			host.Ports = append(host.Ports, Port{
+				Protocol:  rawPort.Protocol,
+				Port:      number,
+				Service:   rawPort.Service.Name,
+				Product:   rawPort.Service.Product,
+				Version:   rawPort.Service.Version,
+				ExtraInfo: rawPort.Service.ExtraInfo,
+				OSType:    rawPort.Service.OSType,
+			})
+			if host.OS == "" && rawPort.Service.OSType != "" {
+				host.OS = rawPort.Service.OSType
+			}
+		}
+
+		if host.Address == "" && len(host.Hostnames) == 0 {
+			continue
+		}
+		run.Hosts = append(run.Hosts, host)
+	}
+	return run, nil
+}
@@ -0,0 +1,91 @@
+package nmap
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func parseFixture(t *testing.T) *Run {
+	t.Helper()
+	file, err := os.Open("testdata/scan.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	run, err := Parse(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return run
+}
+
+func TestParse(t *testing.T) {
+	run := parseFixture(t)
+
+	if run.Scanner != "nmap" || run.Version != "7.94" {
+		t.Errorf("scanner %q %q, want nmap 7.94", run.Scanner, run.Version)
+	}
+	if want := time.Unix(1700000000, 0).UTC(); !run.Start.Equal(want) {
+		t.Errorf("start %v, want %v", run.Start, want)
+	}
+
+	// The down host is dropped; the duplicate of 10.0.0.5 is kept, merging is up to the caller
+	var addresses []string
+	for _, host := range run.Hosts {
+		addresses = append(addresses, host.Address)
+	}
+	if want := []string{"10.0.0.5", "fe80::20c:29ff:fe12:3456", "10.0.0.5"}; !reflect.DeepEqual(addresses, want) {
+		t.Fatalf("hosts %v, want %v", addresses, want)
+	}
+
+	web := run.Hosts[0]
+	if want := []string{"web.corp.local", "ptr-10-0-0-5.corp.local"}; !reflect.DeepEqual(web.Hostnames, want) {
+		t.Errorf("hostnames %v, want %v", web.Hostnames, want)
+	}
+	if web.OS != "Linux 5.0 - 5.14" {
+		t.Errorf("OS %q, want the most accurate match", web.OS)
+	}
+	// filtered and open|filtered ports are not open
+	want := []Port{
+		{Protocol: "tcp", Port: 22, Service: "ssh", Product: "OpenSSH", Version: "8.9p1", ExtraInfo: "Ubuntu Linux; protocol 2.0", OSType: "Linux"},
+		{Protocol: "tcp", Port: 80, Service: "http", Product: "nginx"},
+	}
+	if !reflect.DeepEqual(web.Ports, want) {
+		t.Errorf("ports %+v, want %+v", web.Ports, want)
+	}
+
+	ipv6 := run.Hosts[1]
+	if len(ipv6.Hostnames) != 0 || len(ipv6.Ports) != 1 || ipv6.Ports[0].Port != 53 || ipv6.Ports[0].Protocol != "udp" {
+		t.Errorf("ipv6 host %+v", ipv6)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		port Port
+		want string
+	}{
+		{Port{Product: "OpenSSH", Version: "8.9p1", ExtraInfo: "Ubuntu Linux"}, "OpenSSH 8.9p1 (Ubuntu Linux)"},
+		{Port{Product: "nginx"}, "nginx"},
+		{Port{ExtraInfo: "protocol 2.0"}, "(protocol 2.0)"},
+		{Port{}, ""},
+	}
+	for _, test := range tests {
+		if got := test.port.Describe(); got != test.want {
+			t.Errorf("Describe() = %q, want %q", got, test.want)
+		}
+	}
+}
+
+func TestParseRejectsOtherDocuments(t *testing.T) {
+	for _, document := range []string{"", "not xml", "<report><host/></report>", "<nmaprun><host>"} {
+		if _, err := Parse(strings.NewReader(document)); !errors.Is(err, ErrNotNmap) {
+			t.Errorf("Parse(%q) = %v, want ErrNotNmap", document, err)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+// Package pdf writes simple text documents (titles, headings, paragraphs and label/value fields)
+// as PDF using the standard Helvetica fonts. Pages are written to the output as soon as they are
+// full, so only the current page is kept in memory.
+package pdf
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	pageWidth  = 595.0 // A4 in points
+	pageHeight = 842.0
+	margin     = 50.0
+
+	fontRegular = "F1"
+	fontBold    = "F2"
+
+	// Objects 1 to 4 are written last, once every page is known
+	catalogObject     = 1
+	pagesObject       = 2
+	regularFontObject = 3
+	boldFontObject    = 4
+	firstPageObject   = 5
+)
+
+// Writer lays out text on A4 pages and streams them to an io.Writer. Close must be called to finish the document.
+type Writer struct {
+	out     *bufio.Writer
+	counter *countingWriter
+	offsets map[int]int64
+	next    int
+	pages   []int
+	content bytes.Buffer
+	y       float64
+	started bool
+	err     error
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func NewWriter(w io.Writer) *Writer {
+	counter := &countingWriter{w: w}
+	return &Writer{
+		out:     bufio.NewWriter(counter),
+		counter: counter,
+		offsets: make(map[int]int64),
+		next:    firstPageObject,
+		y:       pageHeight - margin,
+	}
+}
+
+func (w *Writer) offset() int64 {
+	return w.counter.n + int64(w.out.Buffered())
+}
+
+func (w *Writer) printf(format string, args ...interface{}) {
+	if w.err != nil {
+		return
+	}
+	_, w.err = fmt.Fprintf(w.out, format, args...)
+}
+
+func (w *Writer) beginObject(id int) {
+	w.offsets[id] = w.offset()
+	w.printf("%d 0 obj\n", id)
+}
+
+func (w *Writer) start() {
+	if w.started {
+		return
+	}
+	w.started = true
+	w.printf("%%PDF-1.4\n%%\xe2\xe3\xcf\xd3\n")
+}
+
+// flushPage writes the current page and its content stream, then starts a new page.
+func (w *Writer) flushPage() {
+	w.start()
+
+	contentID := w.next
+	pageID := w.next + 1
+	w.next += 2
+
+	w.beginObject(contentID)
+	w.printf("<< /Length %d >>\nstream\n", w.content.Len())
+	if w.err == nil {
+		_, w.err = w.content.WriteTo(w.out)
+	}
+	w.printf("\nendstream\nendobj\n")
+
+	w.beginObject(pageID)
+	w.printf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.0f %.0f] /Contents %d 0 R /Resources << /Font << /%s %d 0 R /%s %d 0 R >> >> >>\nendobj\n",
+		pagesObject, pageWidth, pageHeight, contentID, fontRegular, regularFontObject, fontBold, boldFontObject)
+	w.pages = append(w.pages, pageID)
+
+	w.content.Reset()
+	w.y = pageHeight - margin
+	if w.err == nil {
+		w.err = w.out.Flush()
+	}
+}
+
+// PageBreak starts a new page unless the current one is empty.
+func (w *Writer) PageBreak() {
+	if w.content.Len() > 0 {
+		w.flushPage()
+	}
+}
+
+func (w *Writer) line(font string, size, x float64, text string) {
+	leading := size * 1.4
+	if w.y-leading < margin {
+		w.flushPage()
+	}
+	w.y -= leading
+	fmt.Fprintf(&w.content, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, w.y, escape(text))
+}
+
+func (w *Writer) wrapped(font string, size, indent float64, text string) {
+	width := pageWidth - 2*margin - indent
+	for _, paragraph := range strings.Split(text, "\n") {
+		for _, line := range wrap(paragraph, font == fontBold, size, width) {
+			w.line(font, size, margin+indent, line)
+		}
+	}
+}
+
+// Space adds vertical space.
+func (w *Writer) Space() {
+	w.y -= 8
+}
+
+func (w *Writer) Title(text string) {
+	w.wrapped(fontBold, 18, 0, text)
+	w.Space()
+}
+
+func (w *Writer) Heading(text string) {
+	w.Space()
+	w.wrapped(fontBold, 13, 0, text)
+}
+
+func (w *Writer) Paragraph(text string) {
+	w.wrapped(fontRegular, 10, 0, text)
+}
+
+// Field writes a bold label followed by its value, wrapping long values below the label.
+func (w *Writer) Field(label, value string) {
+	w.wrapped(fontBold, 10, 0, label)
+	w.y += 10 * 1.4
+	labelWidth := textWidth(label+" ", true, 10)
+	if labelWidth > 150 {
+		w.y -= 10 * 1.4
+		labelWidth = 0
+	}
+	w.wrapped(fontRegular, 10, labelWidth, value)
+}
+
+// Close writes the last page, the page tree, the fonts and the cross-reference table.
+func (w *Writer) Close() error {
+	if w.content.Len() > 0 || len(w.pages) == 0 {
+		w.flushPage()
+	}
+
+	kids := make([]string, len(w.pages))
+	for i, id := range w.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", id)
+	}
+
+	w.beginObject(catalogObject)
+	w.printf("<< /Type /Catalog /Pages %d 0 R >>\nendobj\n", pagesObject)
+	w.beginObject(pagesObject)
+	w.printf("<< /Type /Pages /Kids [%s] /Count %d >>\nendobj\n", strings.Join(kids, " "), len(w.pages))
+	w.beginObject(regularFontObject)
+	w.printf("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>\nendobj\n")
+	w.beginObject(boldFontObject)
+	w.printf("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>\nendobj\n")
+
+	xref := w.offset()
+	w.printf("xref\n0 %d\n0000000000 65535 f \n", w.next)
+	for id := 1; id < w.next; id++ {
+		w.printf("%010d 00000 n \n", w.offsets[id])
+	}
+	w.printf("trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", w.next, catalogObject, xref)
+
+	if w.err != nil {
+		return w.err
+	}
+	return w.out.Flush()
+}
@@ -0,0 +1,105 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDocumentStructure(t *testing.T) {
+	var out bytes.Buffer
+	w := NewWriter(&out)
+	w.Title("Report #1 (draft)")
+	for i := 0; i < 120; i++ {
+		w.Heading(fmt.Sprintf("Section %d", i))
+		w.Field("Severity:", "critical")
+		w.Paragraph(strings.Repeat("Lateral movement through the domain controller. ", 8))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	document := out.String()
+
+	if !strings.HasPrefix(document, "%PDF-") || !strings.HasSuffix(document, "%%EOF\n") {
+		t.Fatal("missing PDF header or trailer")
+	}
+
+	count := regexp.MustCompile(`/Type /Pages /Kids \[[^\]]*\] /Count (\d+)`).FindStringSubmatch(document)
+	if count == nil {
+		t.Fatal("missing page tree")
+	}
+	if pages, _ := strconv.Atoi(count[1]); pages < 2 {
+		t.Errorf("got %d pages, want the text to overflow the first page", pages)
+	}
+
+	// Every cross-reference entry must point at the start of its object
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(document)
+	if startxref == nil {
+		t.Fatal("missing startxref")
+	}
+	xref, _ := strconv.Atoi(startxref[1])
+	if !strings.HasPrefix(document[xref:], "xref\n") {
+		t.Fatalf("startxref %d does not point at the cross-reference table", xref)
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(document[xref:], -1)
+	if len(entries) == 0 {
+		t.Fatal("empty cross-reference table")
+	}
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		if want := fmt.Sprintf("%d 0 obj", i+1); !strings.HasPrefix(document[offset:], want) {
+			t.Errorf("object %d: offset %d does not point at %q", i+1, offset, want)
+		}
+	}
+
+	if !strings.Contains(document, `(Report #1 \(draft\)) Tj`) {
+		t.Error("parentheses in the title are not escaped")
+	}
+}
+
+func TestEmptyDocument(t *testing.T) {
+	var out bytes.Buffer
+	if err := NewWriter(&out).Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "/Count 1") {
+		t.Error("an empty document must still have one page")
+	}
+}
+
+func TestWrap(t *testing.T) {
+	const width = 100.0
+	text := "The quick brown fox jumps over the lazy dog " + strings.Repeat("x", 80)
+	lines := wrap(text, false, 10, width)
+	if len(lines) < 3 {
+		t.Fatalf("got %d lines, want the text wrapped", len(lines))
+	}
+	for _, line := range lines {
+		if textWidth(line, false, 10) > width {
+			t.Errorf("line %q is wider than %.0f", line, width)
+		}
+	}
+	if joined := strings.ReplaceAll(strings.Join(lines, ""), " ", ""); joined != strings.ReplaceAll(text, " ", "") {
+		t.Error("wrapping lost text")
+	}
+
+	if lines := wrap("", false, 10, width); len(lines) != 1 || lines[0] != "" {
+		t.Errorf("wrap of empty text = %q", lines)
+	}
+}
+
+func TestEscape(t *testing.T) {
+	tests := map[string]string{
+		`a(b)c\d`: `a\(b\)c\\d`,
+		"café":    "caf\xe9",
+		"日本":      "??",
+	}
+	for text, want := range tests {
+		if got := escape(text); got != want {
+			t.Errorf("escape(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package pdf
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// helveticaWidths are the Helvetica glyph widths (1/1000 em) of the printable ASCII characters, from space.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// winAnsi maps the characters outside Latin-1 that WinAnsiEncoding supports.
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88,
+	'‰': 0x89, 'Š': 0x8a, '‹': 0x8b, 'Œ': 0x8c, 'Ž': 0x8e, '‘': 0x91, '’': 0x92, '“': 0x93,
+	'”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9a, '›': 0x9b,
+	'œ': 0x9c, 'ž': 0x9e, 'Ÿ': 0x9f,
+}
+
+func charWidth(r rune, bold bool) float64 {
+	width := 556
+	if r >= ' ' && r <= '~' {
+		width = helveticaWidths[r-' ']
+	}
+	if bold {
+		// Helvetica-Bold is slightly wider; overestimating keeps lines inside the margins
+		return float64(width) * 1.08
+	}
+	return float64(width)
+}
+
+func textWidth(text string, bold bool, size float64) float64 {
+	total := 0.0
+	for _, r := range text {
+		total += charWidth(r, bold)
+	}
+	return total * size / 1000
+}
+
+// wrap splits text into lines no wider than width, breaking on spaces and inside words longer than a line.
+func wrap(text string, bold bool, size, width float64) []string {
+	text = strings.TrimRight(strings.ReplaceAll(text, "\t", "    "), " \r")
+	if text == "" {
+		return []string{""}
+	}
+
+	var lines []string
+	var line strings.Builder
+	lineWidth := 0.0
+	spaceWidth := charWidth(' ', bold) * size / 1000
+
+	for _, word := range strings.Split(text, " ") {
+		wordWidth := textWidth(word, bold, size)
+		if line.Len() > 0 && lineWidth+spaceWidth+wordWidth <= width {
+			line.WriteByte(' ')
+			line.WriteString(word)
+			lineWidth += spaceWidth + wordWidth
+			continue
+		}
+		if line.Len() > 0 {
+			lines = append(lines, line.String())
+			line.Reset()
+			lineWidth = 0
+		}
+		for _, r := range word {
+			runeWidth := charWidth(r, bold) * size / 1000
+			if line.Len() > 0 && lineWidth+runeWidth > width {
+				lines = append(lines, line.String())
+				line.Reset()
+				lineWidth = 0
+			}
+			line.WriteRune(r)
+			lineWidth += runeWidth
+		}
+	}
+	return append(lines, line.String())
+}
+
+// escape encodes text as a WinAnsi PDF string body. Characters the fonts cannot show become '?'.
+func escape(text string) string {
+	var b strings.Builder
+	for len(text) > 0 {
+		r, size := utf8.DecodeRuneInString(text)
+		text = text[size:]
+
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= ' ' && r <= '~':
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			b.WriteByte(byte(r))
+		default:
+			if code, ok := winAnsi[r]; ok {
+				b.WriteByte(code)
+			} else {
+				b.WriteByte('?')
+			}
+		}
+	}
+	return b.String()
+}
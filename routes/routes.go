@@ -18,6 +18,19 @@ func SetupRoutes(r *gin.Engine) {
 	auth := api.Group("/")
 	auth.Use(middleware.AuthRequired())
 
+	// Seasons and badges (any authenticated user)
+	auth.GET("/seasons", controllers.GetSeasons)
+	auth.GET("/seasons/:id/leaderboard", controllers.GetSeasonLeaderboard)
+	auth.GET("/badges", controllers.GetBadges)
+	auth.GET("/templates", controllers.GetReportTemplates)
+	auth.GET("/templates/:id", controllers.GetReportTemplate)
+	auth.GET("/techniques", controllers.GetTechniques)
+
+	// Notifications (any authenticated user)
+	auth.GET("/notifications", controllers.GetNotifications)
+	auth.POST("/notifications/read", controllers.MarkAllNotificationsRead)
+	auth.POST("/notifications/:id/read", controllers.MarkNotificationRead)
+
 	// Andrei routes (admin only)
 	andrei := auth.Group("/admin")
 	andrei.Use(middleware.RequireAndrei())
@@ -26,11 +39,68 @@ func SetupRoutes(r *gin.Engine) {
 		andrei.GET("/users/:id", controllers.GetUserByID)
 		andrei.DELETE("/users/:id", controllers.DeleteUser)
 		andrei.POST("/rewards", controllers.CreateReward)
+		andrei.PUT("/rewards/:id", controllers.UpdateReward)
 		andrei.GET("/stats", controllers.GetPlatformStats)
 		andrei.GET("/demons/ranking", controllers.GetDemonRanking)
+		andrei.GET("/ranking/formula", controllers.GetScoringFormula)
+		andrei.PUT("/ranking/formula", controllers.UpdateScoringFormula)
+		andrei.POST("/ranking/snapshots", controllers.CreateRankingSnapshot)
+		andrei.GET("/ranking/movement", controllers.GetRankMovement)
+		andrei.GET("/ranking/climbers", controllers.GetTopClimbers)
+		andrei.GET("/demons/:id/timeline", controllers.GetDemonTimeline)
+		andrei.GET("/demons/:id/techniques", controllers.GetDemonTechniqueStats)
+		andrei.GET("/techniques/stats", controllers.GetTechniqueStats)
+		andrei.GET("/reports", controllers.GetReports)
+		andrei.GET("/reports/queue", controllers.GetReviewQueue)
+		andrei.GET("/reports/search", controllers.SearchReports)
+		andrei.GET("/reports/export", controllers.ExportReports)
+		andrei.GET("/reports/:id", controllers.GetReport)
+		andrei.POST("/reports/:id/review", controllers.ReviewReport)
+		andrei.PUT("/reports/:id/status", controllers.AndreiUpdateReportStatus)
+		andrei.GET("/reports/:id/history", controllers.GetReportHistory)
+		andrei.GET("/reports/:id/export", controllers.ExportReport)
+		andrei.GET("/reports/:id/revisions", controllers.GetReportRevisions)
+		andrei.GET("/reports/:id/revisions/diff", controllers.GetReportRevisionDiff)
+		andrei.PUT("/reports/:id/due-date", controllers.SetReportDueDate)
+		andrei.GET("/assignments", controllers.GetAssignments)
+		andrei.DELETE("/assignments/:id", controllers.UnassignVictim)
+		andrei.PUT("/assignments/:id/due-date", controllers.SetAssignmentDueDate)
+		andrei.GET("/allocations/preview", controllers.PreviewAllocation)
+		andrei.POST("/allocations", controllers.CommitAllocation)
+		andrei.GET("/victims/:id/dossier", controllers.GetVictimDossier)
+		andrei.GET("/victims/:id/ownership", controllers.GetVictimOwnership)
+		andrei.GET("/victims/:id/assets", controllers.GetVictimAssets)
+		andrei.GET("/assets", controllers.SearchAssets)
+		andrei.PUT("/assets/:id", controllers.UpdateAsset)
+		andrei.DELETE("/assets/:id", controllers.DeleteAsset)
+		andrei.GET("/disputes", controllers.GetDisputes)
+		andrei.POST("/disputes/:id/resolve", controllers.ResolveDispute)
+		andrei.GET("/overdue", controllers.GetOverdueWork)
+		andrei.GET("/missed-deadlines", controllers.GetMissedDeadlines)
+		andrei.GET("/reports/:id/attachments", controllers.GetReportAttachments)
+		andrei.GET("/attachments/:id/download", controllers.DownloadAttachment)
+		andrei.GET("/attachments/:id/custody", controllers.GetAttachmentCustody)
+		andrei.GET("/reports/:id/comments", controllers.GetReportComments)
+		andrei.POST("/reports/:id/comments", controllers.CreateComment)
+		andrei.PUT("/comments/:id", controllers.UpdateComment)
+		andrei.DELETE("/comments/:id", controllers.DeleteComment)
 		andrei.GET("/posts", controllers.GetAllPosts)
+		andrei.PUT("/posts/:id", controllers.UpdatePost)
 		andrei.DELETE("/posts/:id", controllers.DeletePost)
 		andrei.POST("/posts", controllers.CreateAndreiPost)
+		andrei.POST("/seasons", controllers.CreateSeason)
+		andrei.POST("/seasons/:id/close", controllers.CloseSeason)
+		andrei.POST("/badges", controllers.CreateBadge)
+		andrei.DELETE("/badges/:id", controllers.DeleteBadge)
+		andrei.POST("/templates", controllers.CreateReportTemplate)
+		andrei.DELETE("/templates/:id", controllers.DeleteReportTemplate)
+		andrei.GET("/demons/:id/achievements", controllers.GetDemonAchievements)
+		andrei.GET("/appeals", controllers.GetAppeals)
+		andrei.POST("/appeals/:id/accept", controllers.AcceptAppeal)
+		andrei.POST("/appeals/:id/reject", controllers.RejectAppeal)
+		andrei.GET("/transfers", controllers.GetTransfers)
+		andrei.POST("/transfers/:id/approve", controllers.ApproveTransfer)
+		andrei.POST("/transfers/:id/reject", controllers.RejectTransfer)
 	}
 
 	// Demon routes
@@ -41,9 +111,48 @@ func SetupRoutes(r *gin.Engine) {
 		demons.POST("/victims", controllers.AssignVictim)
 		demons.POST("/reports", controllers.CreateReport)
 		demons.GET("/stats", controllers.GetMyStats)
+		demons.GET("/achievements", controllers.GetMyAchievements)
+		demons.GET("/timeline", controllers.GetMyTimeline)
+		demons.POST("/appeals", controllers.CreateAppeal)
+		demons.GET("/appeals", controllers.GetMyAppeals)
+		demons.POST("/transfers", controllers.CreateTransfer)
+		demons.GET("/transfers", controllers.GetMyTransfers)
 		demons.GET("/victims", controllers.GetMyVictims)
+		demons.PUT("/victims/:id/status", controllers.UpdateVictimStatus)
+		demons.POST("/victims/:id/release", controllers.ReleaseVictim)
+		demons.POST("/victims/:id/transfer", controllers.CreateVictimTransfer)
+		demons.POST("/victims/:id/dispute", controllers.OpenVictimDispute)
+		demons.GET("/victims/:id/assets", controllers.GetVictimAssets)
+		demons.POST("/victims/:id/assets", controllers.CreateAsset)
+		demons.GET("/assets", controllers.SearchAssets)
+		demons.PUT("/assets/:id", controllers.UpdateAsset)
+		demons.DELETE("/assets/:id", controllers.DeleteAsset)
+		demons.PUT("/reports/:id/assets", controllers.SetReportAssets)
+		demons.PUT("/reports/:id/techniques", controllers.SetReportTechniques)
+		demons.GET("/techniques/stats", controllers.GetMyTechniqueStats)
+		demons.POST("/victims/:id/imports", controllers.CreateScanImport)
+		demons.GET("/imports", controllers.GetMyScanImports)
+		demons.GET("/imports/:id", controllers.GetScanImport)
+		demons.POST("/imports/:id/commit", controllers.CommitScanImport)
+		demons.GET("/victim-transfers", controllers.GetMyVictimTransfers)
+		demons.POST("/victim-transfers/:id/accept", controllers.AcceptVictimTransfer)
+		demons.POST("/victim-transfers/:id/decline", controllers.DeclineVictimTransfer)
+		demons.POST("/victim-transfers/:id/cancel", controllers.CancelVictimTransfer)
 		demons.GET("/reports", controllers.GetMyReports)
+		demons.GET("/reports/search", controllers.SearchMyReports)
 		demons.PUT("/reports/:id", controllers.UpdateReportStatus)
+		demons.PATCH("/reports/:id", controllers.EditReport)
+		demons.GET("/reports/:id/revisions", controllers.GetReportRevisions)
+		demons.GET("/reports/:id/revisions/diff", controllers.GetReportRevisionDiff)
+		demons.GET("/reports/:id/history", controllers.GetMyReportHistory)
+		demons.GET("/overdue", controllers.GetMyOverdueWork)
+		demons.POST("/reports/:id/attachments", controllers.UploadAttachment)
+		demons.GET("/reports/:id/attachments", controllers.GetReportAttachments)
+		demons.GET("/attachments/:id/download", controllers.DownloadAttachment)
+		demons.GET("/reports/:id/comments", controllers.GetReportComments)
+		demons.POST("/reports/:id/comments", controllers.CreateComment)
+		demons.PUT("/comments/:id", controllers.UpdateComment)
+		demons.DELETE("/comments/:id", controllers.DeleteComment)
 		demons.POST("/posts", controllers.CreateDemonPost)
 	}
 
@@ -115,6 +115,12 @@ if [ -n "$ANDREI_AUTH" ]; then
     test_endpoint "GET" "/admin/users/2" "200" "Ver usuario específico" "$ANDREI_AUTH"
     test_endpoint "GET" "/admin/stats" "200" "Ver estadísticas de plataforma" "$ANDREI_AUTH"
     test_endpoint "GET" "/admin/demons/ranking" "200" "Ver ranking de demonios" "$ANDREI_AUTH"
+    test_endpoint "GET" "/admin/reports?severity=critical&min_score=9" "200" "Filtrar reportes por severidad" "$ANDREI_AUTH"
+    test_endpoint "GET" "/admin/reports/export?format=csv" "200" "Exportar reportes en CSV" "$ANDREI_AUTH"
+    test_endpoint "GET" "/admin/reports/export?format=pdf" "200" "Exportar reportes en PDF" "$ANDREI_AUTH"
+    test_endpoint "GET" "/admin/reports/export?format=xml" "400" "Formato de exportación inválido debe fallar" "$ANDREI_AUTH"
+    test_endpoint "GET" "/admin/techniques/stats" "200" "Ver uso de técnicas" "$ANDREI_AUTH"
+    test_endpoint "GET" "/admin/demons/2/techniques" "200" "Ver técnicas de un demonio" "$ANDREI_AUTH"
     test_endpoint "GET" "/admin/posts" "200" "Ver todos los posts" "$ANDREI_AUTH"
     test_endpoint "POST" "/admin/posts" "201" "Crear post como Andrei" "$ANDREI_AUTH" '{"title":"Test Post Andrei","body":"Test content","media":""}'
     test_endpoint "POST" "/admin/rewards" "201" "Crear recompensa" "$ANDREI_AUTH" '{"demon_id":2,"type":"reward","title":"Test Reward","description":"Test reward","points":100}'
@@ -128,7 +134,18 @@ if [ -n "$DEMON_AUTH" ]; then
     test_endpoint "GET" "/demons/victims" "200" "Ver mis víctimas" "$DEMON_AUTH"
     test_endpoint "POST" "/demons/reports" "201" "Crear reporte" "$DEMON_AUTH" '{"victim_id":9,"title":"Test Report","description":"Test report description"}'
     test_endpoint "GET" "/demons/reports" "200" "Ver mis reportes" "$DEMON_AUTH"
+    test_endpoint "POST" "/demons/reports" "201" "Crear reporte con vector CVSS" "$DEMON_AUTH" '{"victim_id":9,"title":"Test CVSS Report","description":"Test report with severity","cvss_vector":"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}'
+    test_endpoint "POST" "/demons/reports" "400" "Vector CVSS inválido debe fallar" "$DEMON_AUTH" '{"victim_id":9,"title":"Test CVSS Report","description":"Test report","cvss_vector":"CVSS:3.1/AV:N/AC:L"}'
+    test_endpoint "GET" "/demons/reports?severity=critical,high&sort=severity" "200" "Filtrar mis reportes por severidad" "$DEMON_AUTH"
+    test_endpoint "GET" "/demons/reports?severity=extreme" "400" "Severidad desconocida debe fallar" "$DEMON_AUTH"
     test_endpoint "GET" "/demons/stats" "200" "Ver mis estadísticas" "$DEMON_AUTH"
+    test_endpoint "GET" "/demons/imports" "200" "Ver mis importaciones de escaneos" "$DEMON_AUTH"
+    test_endpoint "GET" "/techniques?tactic=initial-access" "200" "Ver técnicas de acceso inicial" "$DEMON_AUTH"
+    test_endpoint "GET" "/techniques?tactic=unknown" "400" "Táctica desconocida debe fallar" "$DEMON_AUTH"
+    test_endpoint "POST" "/demons/reports" "201" "Crear reporte con técnicas" "$DEMON_AUTH" '{"victim_id":9,"title":"Test Technique Report","description":"Test report with techniques","technique_ids":["T1566.001","t1078"]}'
+    test_endpoint "POST" "/demons/reports" "400" "Técnica desconocida debe fallar" "$DEMON_AUTH" '{"victim_id":9,"title":"Test","description":"Test","technique_ids":["T9999"]}'
+    test_endpoint "GET" "/demons/reports?technique=T1566" "200" "Filtrar mis reportes por técnica" "$DEMON_AUTH"
+    test_endpoint "GET" "/demons/techniques/stats" "200" "Ver mis técnicas" "$DEMON_AUTH"
     test_endpoint "POST" "/demons/posts" "201" "Crear post como demonio" "$DEMON_AUTH" '{"title":"Test Post Demon","body":"Test content from demon","media":""}'
 fi
 